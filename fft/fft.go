@@ -0,0 +1,234 @@
+package fft
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"sync"
+)
+
+// maxDirectRadix is the largest prime factor transformed directly. Lengths with a larger prime factor use
+// Bluestein's algorithm, which keeps the cost O(n log n) for prime lengths.
+const maxDirectRadix = 31
+
+// Plan holds the factorization and twiddle factors of a complex FFT of a given length. A plan can be reused for
+// any number of transforms and is safe for concurrent use.
+type Plan struct {
+	n         int
+	factors   []int // radix of each stage, the product of the factors is n
+	twiddles  []complex128
+	bluestein *bluesteinPlan
+	scratch   sync.Pool
+}
+
+// bluesteinPlan evaluates a transform of arbitrary length as a circular convolution of power of 2 length.
+type bluesteinPlan struct {
+	chirp  []complex128 // exp(-i*pi*k^2/n)
+	kernel []complex128 // transform of the conjugate chirp
+	inner  *Plan
+}
+
+// NewPlan returns a plan for complex transforms of length n.
+func NewPlan(n int) (*Plan, error) {
+	if n < 1 {
+		return nil, errors.New("transform length must be positive")
+	}
+	p := &Plan{n: n}
+	p.scratch.New = func() interface{} {
+		buffer := make([]complex128, p.bufferLength())
+		return &buffer
+	}
+
+	factors := factorize(n)
+	if factors[len(factors)-1] > maxDirectRadix {
+		p.bluestein = newBluesteinPlan(n)
+		return p, nil
+	}
+	p.factors = factors
+	p.twiddles = make([]complex128, n)
+	for k := range p.twiddles {
+		p.twiddles[k] = cmplx.Exp(complex(0, -2*math.Pi*float64(k)/float64(n)))
+	}
+	return p, nil
+}
+
+// Len returns the transform length of the plan.
+func (p *Plan) Len() int {
+	return p.n
+}
+
+func (p *Plan) bufferLength() int {
+	if p.bluestein != nil {
+		return len(p.bluestein.kernel)
+	}
+	// copy of the input and the radix scratch of the generic butterfly
+	return p.n + maxDirectRadix
+}
+
+// factorize returns the radices of the stages, fours first, then twos and the remaining primes in increasing order.
+func factorize(n int) []int {
+	var factors []int
+	for n%4 == 0 {
+		factors = append(factors, 4)
+		n /= 4
+	}
+	for n%2 == 0 {
+		factors = append(factors, 2)
+		n /= 2
+	}
+	for p := 3; p*p <= n; p += 2 {
+		for n%p == 0 {
+			factors = append(factors, p)
+			n /= p
+		}
+	}
+	if n > 1 || len(factors) == 0 {
+		factors = append(factors, n)
+	}
+	return factors
+}
+
+func newBluesteinPlan(n int) *bluesteinPlan {
+	m := 1
+	for m < 2*n-1 {
+		m *= 2
+	}
+	inner, _ := NewPlan(m)
+	b := &bluesteinPlan{chirp: make([]complex128, n), kernel: make([]complex128, m), inner: inner}
+	for k := 0; k < n; k++ {
+		// k^2 mod 2n keeps the argument small for long transforms
+		angle := math.Pi * float64((k*k)%(2*n)) / float64(n)
+		b.chirp[k] = cmplx.Exp(complex(0, -angle))
+	}
+	b.kernel[0] = cmplx.Conj(b.chirp[0])
+	for k := 1; k < n; k++ {
+		b.kernel[k] = cmplx.Conj(b.chirp[k])
+		b.kernel[m-k] = b.kernel[k]
+	}
+	inner.Forward(b.kernel, b.kernel)
+	return b
+}
+
+// Forward computes the unnormalized forward transform of src into dst. Both must have the length of the plan and
+// may be the same slice.
+func (p *Plan) Forward(dst, src []complex128) {
+	p.checkLength(dst, src)
+	buffer := p.scratch.Get().(*[]complex128)
+	defer p.scratch.Put(buffer)
+	if p.bluestein != nil {
+		p.bluestein.transform(dst, src, *buffer)
+		return
+	}
+	input := (*buffer)[:p.n]
+	copy(input, src)
+	p.work(dst, input, 1, p.factors, (*buffer)[p.n:])
+}
+
+// Inverse computes the inverse transform of src into dst, normalized by 1/n so that Inverse(Forward(x)) = x.
+func (p *Plan) Inverse(dst, src []complex128) {
+	p.checkLength(dst, src)
+	for i, value := range src {
+		dst[i] = cmplx.Conj(value)
+	}
+	p.Forward(dst, dst)
+	scale := 1 / float64(p.n)
+	for i, value := range dst {
+		dst[i] = complex(real(value)*scale, -imag(value)*scale)
+	}
+}
+
+func (p *Plan) checkLength(dst, src []complex128) {
+	if len(dst) != p.n || len(src) != p.n {
+		panic("fft: slice length does not match the plan")
+	}
+}
+
+// work is the recursive decimation in time of the input read with the given stride into out.
+func (p *Plan) work(out, in []complex128, stride int, factors []int, scratch []complex128) {
+	radix := factors[0]
+	m := len(out) / radix
+	if m == 1 {
+		for q := 0; q < radix; q++ {
+			out[q] = in[q*stride]
+		}
+	} else {
+		for q := 0; q < radix; q++ {
+			p.work(out[q*m:(q+1)*m], in[q*stride:], stride*radix, factors[1:], scratch)
+		}
+	}
+
+	switch radix {
+	case 2:
+		p.butterfly2(out, stride, m)
+	case 4:
+		p.butterfly4(out, stride, m)
+	default:
+		p.butterflyGeneric(out, stride, m, radix, scratch)
+	}
+}
+
+func (p *Plan) butterfly2(out []complex128, stride, m int) {
+	for k := 0; k < m; k++ {
+		t := out[k+m] * p.twiddles[k*stride]
+		out[k+m] = out[k] - t
+		out[k] += t
+	}
+}
+
+func (p *Plan) butterfly4(out []complex128, stride, m int) {
+	for k := 0; k < m; k++ {
+		s0 := out[k+m] * p.twiddles[k*stride]
+		s1 := out[k+2*m] * p.twiddles[2*k*stride]
+		s2 := out[k+3*m] * p.twiddles[3*k*stride]
+		s5 := out[k] - s1
+		s4 := out[k] + s1
+		s3 := s0 + s2
+		d := s0 - s2
+		// -i * d
+		md := complex(imag(d), -real(d))
+		out[k] = s4 + s3
+		out[k+2*m] = s4 - s3
+		out[k+m] = s5 + md
+		out[k+3*m] = s5 - md
+	}
+}
+
+func (p *Plan) butterflyGeneric(out []complex128, stride, m, radix int, scratch []complex128) {
+	for u := 0; u < m; u++ {
+		for q := 0; q < radix; q++ {
+			scratch[q] = out[u+q*m]
+		}
+		for q1 := 0; q1 < radix; q1++ {
+			k := u + q1*m
+			index := 0
+			value := scratch[0]
+			for q := 1; q < radix; q++ {
+				index += stride * k
+				if index >= p.n {
+					index -= p.n
+				}
+				value += scratch[q] * p.twiddles[index]
+			}
+			out[k] = value
+		}
+	}
+}
+
+func (b *bluesteinPlan) transform(dst, src, buffer []complex128) {
+	n := len(b.chirp)
+	for k := range buffer {
+		if k < n {
+			buffer[k] = src[k] * b.chirp[k]
+		} else {
+			buffer[k] = 0
+		}
+	}
+	b.inner.Forward(buffer, buffer)
+	for k, value := range b.kernel {
+		buffer[k] *= value
+	}
+	b.inner.Inverse(buffer, buffer)
+	for k := 0; k < n; k++ {
+		dst[k] = buffer[k] * b.chirp[k]
+	}
+}
@@ -0,0 +1,151 @@
+package fft
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// naiveDFT returns the unnormalized forward transform of x by direct summation.
+func naiveDFT(x []complex128) []complex128 {
+	n := len(x)
+	transform := make([]complex128, n)
+	for k := range transform {
+		for j, value := range x {
+			angle := -2 * math.Pi * float64((k*j)%n) / float64(n)
+			transform[k] += value * cmplx.Exp(complex(0, angle))
+		}
+	}
+	return transform
+}
+
+func randomSignal(n int, seed int64) []float64 {
+	random := rand.New(rand.NewSource(seed))
+	signal := make([]float64, n)
+	for i := range signal {
+		signal[i] = random.NormFloat64()
+	}
+	return signal
+}
+
+func maxError(actual, expected []complex128) float64 {
+	var maxError, scale float64
+	for i := range expected {
+		maxError = math.Max(maxError, cmplx.Abs(actual[i]-expected[i]))
+		scale = math.Max(scale, cmplx.Abs(expected[i]))
+	}
+	return maxError / scale
+}
+
+var testLengths = []int{1, 2, 3, 4, 5, 6, 7, 8, 12, 15, 16, 30, 31, 37, 60, 64, 97, 100, 210, 243, 256, 1000, 1009}
+
+func TestFactorize(t *testing.T) {
+	factors := factorize(2 * 4 * 4 * 9 * 37)
+	expected := []int{4, 4, 2, 3, 3, 37}
+	for i := range expected {
+		if factors[i] != expected[i] {
+			t.Fatalf("Expected factors %v, got %v", expected, factors)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	for _, n := range testLengths {
+		reals := randomSignal(n, int64(n))
+		imaginaries := randomSignal(n, int64(n+1))
+		signal := make([]complex128, n)
+		for i := range signal {
+			signal[i] = complex(reals[i], imaginaries[i])
+		}
+		p, err := NewPlan(n)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		transform := make([]complex128, n)
+		p.Forward(transform, signal)
+		if e := maxError(transform, naiveDFT(signal)); e > 1e-12 {
+			t.Errorf("Expected transform of length %d to match the DFT, got relative error %e", n, e)
+		}
+		p.Inverse(transform, transform)
+		if e := maxError(transform, signal); e > 1e-12 {
+			t.Errorf("Expected inverse of length %d to recover the signal, got relative error %e", n, e)
+		}
+	}
+	if p, _ := NewPlan(37); p.bluestein == nil {
+		t.Errorf("Expected Bluestein's algorithm for a prime length above %d", maxDirectRadix)
+	}
+	if _, err := NewPlan(0); err == nil {
+		t.Errorf("Expected error for zero length")
+	}
+}
+
+func TestRealPlan(t *testing.T) {
+	for _, n := range testLengths {
+		signal := randomSignal(n, int64(n))
+		complexSignal := make([]complex128, n)
+		for i, value := range signal {
+			complexSignal[i] = complex(value, 0)
+		}
+		expected := naiveDFT(complexSignal)[:n/2+1]
+		transform := RFFT(signal)
+		if e := maxError(transform, expected); e > 1e-12 {
+			t.Errorf("Expected real transform of length %d to match the DFT, got relative error %e", n, e)
+		}
+		inverse := IRFFT(transform, n)
+		for i := range signal {
+			if math.Abs(inverse[i]-signal[i]) > 1e-12 {
+				t.Fatalf("Expected inverse of length %d to recover the signal", n)
+			}
+		}
+	}
+}
+
+func TestFFTReal(t *testing.T) {
+	signal := []float64{1, 2, 3, 4, 5}
+	transform := FFTReal(signal)
+	expected := FFT([]complex128{1, 2, 3, 4, 5})
+	if e := maxError(transform, expected); e > 1e-14 {
+		t.Errorf("Expected full real transform to match the complex transform, got relative error %e", e)
+	}
+	if len(FFT(nil)) != 0 || len(RFFT(nil)) != 0 {
+		t.Errorf("Expected empty transforms of empty signals")
+	}
+}
+
+func TestGetPlan(t *testing.T) {
+	first, _ := GetPlan(2)
+	for n := 3; n < 3+2*maxCachedPlans; n++ {
+		if n == 2+maxCachedPlans {
+			// a recently used plan is kept
+			if p, _ := GetPlan(2); p != first {
+				t.Errorf("Expected the cached plan of length 2")
+			}
+		}
+		if _, err := GetPlan(n); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := GetRealPlan(n); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if plans.order.Len() != maxCachedPlans || len(realPlans.plans) != maxCachedPlans {
+		t.Errorf("Expected %d cached plans, got %d and %d", maxCachedPlans, plans.order.Len(), len(realPlans.plans))
+	}
+	if _, ok := plans.plans[3]; ok {
+		t.Errorf("Expected the least recently used plan to be dropped")
+	}
+	if _, err := GetPlan(0); err == nil {
+		t.Errorf("Expected error for a zero length")
+	}
+}
+
+func BenchmarkRFFT(b *testing.B) {
+	signal := randomSignal(5093, 1)
+	p, _ := NewRealPlan(len(signal))
+	transform := make([]complex128, len(signal)/2+1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Forward(transform, signal)
+	}
+}
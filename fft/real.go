@@ -0,0 +1,124 @@
+package fft
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"sync"
+)
+
+// RealPlan holds a plan for transforms of real signals of a given length. Even lengths are transformed as complex
+// signals of half the length. A plan can be reused for any number of transforms and is safe for concurrent use.
+type RealPlan struct {
+	n        int
+	half     *Plan        // complex plan of length n/2 for even n, n for odd n
+	twiddles []complex128 // exp(-2*pi*i*k/n) for k <= n/2
+	scratch  sync.Pool
+}
+
+// NewRealPlan returns a plan for transforms of real signals of length n.
+func NewRealPlan(n int) (*RealPlan, error) {
+	if n < 1 {
+		return nil, errors.New("transform length must be positive")
+	}
+	p := &RealPlan{n: n}
+	var err error
+	if n%2 == 0 {
+		p.half, err = NewPlan(n / 2)
+	} else {
+		p.half, err = NewPlan(n)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.twiddles = make([]complex128, n/2+1)
+	for k := range p.twiddles {
+		p.twiddles[k] = cmplx.Exp(complex(0, -2*math.Pi*float64(k)/float64(n)))
+	}
+	p.scratch.New = func() interface{} {
+		buffer := make([]complex128, p.half.n)
+		return &buffer
+	}
+	return p, nil
+}
+
+// Len returns the signal length of the plan.
+func (p *RealPlan) Len() int {
+	return p.n
+}
+
+// Forward computes the non-negative frequency half of the unnormalized transform of src. dst must have n/2+1
+// elements and src n elements.
+func (p *RealPlan) Forward(dst []complex128, src []float64) {
+	if len(src) != p.n || len(dst) != p.n/2+1 {
+		panic("fft: slice length does not match the plan")
+	}
+	buffer := p.scratch.Get().(*[]complex128)
+	defer p.scratch.Put(buffer)
+	z := *buffer
+
+	if p.n%2 == 1 {
+		for i, value := range src {
+			z[i] = complex(value, 0)
+		}
+		p.half.Forward(z, z)
+		copy(dst, z)
+		return
+	}
+
+	m := p.n / 2
+	for i := range z {
+		z[i] = complex(src[2*i], src[2*i+1])
+	}
+	p.half.Forward(z, z)
+	for k := 0; k <= m; k++ {
+		zk := z[k%m]
+		zc := cmplx.Conj(z[(m-k)%m])
+		even := (zk + zc) / 2
+		odd := (zk - zc) * complex(0, -0.5)
+		dst[k] = even + p.twiddles[k]*odd
+	}
+}
+
+// Inverse computes the real signal whose non-negative frequency half transform is src, normalized by 1/n. dst must
+// have n elements and src n/2+1 elements. The imaginary parts of the zero and, for even n, Nyquist coefficients are
+// ignored.
+func (p *RealPlan) Inverse(dst []float64, src []complex128) {
+	if len(dst) != p.n || len(src) != p.n/2+1 {
+		panic("fft: slice length does not match the plan")
+	}
+	buffer := p.scratch.Get().(*[]complex128)
+	defer p.scratch.Put(buffer)
+	z := *buffer
+
+	if p.n%2 == 1 {
+		z[0] = complex(real(src[0]), 0)
+		for k := 1; k < len(src); k++ {
+			z[k] = src[k]
+			z[p.n-k] = cmplx.Conj(src[k])
+		}
+		p.half.Inverse(z, z)
+		for i := range dst {
+			dst[i] = real(z[i])
+		}
+		return
+	}
+
+	m := p.n / 2
+	for k := 0; k < m; k++ {
+		xk := src[k]
+		xc := cmplx.Conj(src[m-k])
+		if k == 0 {
+			xk = complex(real(xk), 0)
+			xc = complex(real(src[m]), 0)
+		}
+		even := (xk + xc) / 2
+		odd := (xk - xc) / 2 * cmplx.Conj(p.twiddles[k])
+		z[k] = even + complex(0, 1)*odd
+	}
+	p.half.Inverse(z, z)
+	for i, value := range z {
+		dst[2*i] = real(value)
+		dst[2*i+1] = imag(value)
+	}
+}
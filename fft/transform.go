@@ -0,0 +1,120 @@
+package fft
+
+import (
+	"container/list"
+	"math/cmplx"
+	"sync"
+)
+
+// maxCachedPlans is the number of plans of each kind kept by GetPlan and GetRealPlan. The least recently used plan
+// is dropped beyond it; callers transforming many lengths repeatedly should hold their own plans.
+const maxCachedPlans = 32
+
+// planCache is a cache of plans by length that drops the least recently used plan beyond maxCachedPlans.
+type planCache[P any] struct {
+	mutex   sync.Mutex
+	plans   map[int]*list.Element
+	order   *list.List // of cachedPlan, the most recently used first
+	newPlan func(n int) (P, error)
+}
+
+type cachedPlan[P any] struct {
+	n    int
+	plan P
+}
+
+func newPlanCache[P any](newPlan func(n int) (P, error)) *planCache[P] {
+	return &planCache[P]{plans: map[int]*list.Element{}, order: list.New(), newPlan: newPlan}
+}
+
+func (c *planCache[P]) get(n int) (P, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.plans[n]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(cachedPlan[P]).plan, nil
+	}
+	p, err := c.newPlan(n)
+	if err != nil {
+		return p, err
+	}
+	c.plans[n] = c.order.PushFront(cachedPlan[P]{n: n, plan: p})
+	if c.order.Len() > maxCachedPlans {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.plans, oldest.Value.(cachedPlan[P]).n)
+	}
+	return p, nil
+}
+
+var (
+	plans     = newPlanCache(NewPlan)
+	realPlans = newPlanCache(NewRealPlan)
+)
+
+// GetPlan returns the cached complex plan of length n, creating it on first use.
+func GetPlan(n int) (*Plan, error) {
+	return plans.get(n)
+}
+
+// GetRealPlan returns the cached real plan of length n, creating it on first use.
+func GetRealPlan(n int) (*RealPlan, error) {
+	return realPlans.get(n)
+}
+
+// FFT returns the unnormalized forward transform of x.
+func FFT(x []complex128) []complex128 {
+	if len(x) == 0 {
+		return []complex128{}
+	}
+	p, _ := GetPlan(len(x))
+	transform := make([]complex128, len(x))
+	p.Forward(transform, x)
+	return transform
+}
+
+// IFFT returns the inverse transform of x normalized by 1/len(x).
+func IFFT(x []complex128) []complex128 {
+	if len(x) == 0 {
+		return []complex128{}
+	}
+	p, _ := GetPlan(len(x))
+	signal := make([]complex128, len(x))
+	p.Inverse(signal, x)
+	return signal
+}
+
+// RFFT returns the non-negative frequency half, len(x)/2+1 coefficients, of the transform of the real signal x.
+func RFFT(x []float64) []complex128 {
+	if len(x) == 0 {
+		return []complex128{}
+	}
+	p, _ := GetRealPlan(len(x))
+	transform := make([]complex128, len(x)/2+1)
+	p.Forward(transform, x)
+	return transform
+}
+
+// IRFFT returns the real signal of length n whose non-negative frequency half transform is x, normalized by 1/n.
+func IRFFT(x []complex128, n int) []float64 {
+	if n < 1 {
+		return []float64{}
+	}
+	half := make([]complex128, n/2+1)
+	copy(half, x)
+	p, _ := GetRealPlan(n)
+	signal := make([]float64, n)
+	p.Inverse(signal, half)
+	return signal
+}
+
+// FFTReal returns the full, Hermitian symmetric transform of the real signal x.
+func FFTReal(x []float64) []complex128 {
+	half := RFFT(x)
+	transform := make([]complex128, len(x))
+	copy(transform, half)
+	for k := len(half); k < len(x); k++ {
+		transform[k] = cmplx.Conj(half[len(x)-k])
+	}
+	return transform
+}
@@ -0,0 +1,145 @@
+package fourier_spectrum
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+
+	"github.com/geoport/GoQuakeLib/fft"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// SpectrumOptions controls the preprocessing applied before the Fourier transform.
+type SpectrumOptions struct {
+	Window        string  // taper window passed to GetWindow, empty for no taper
+	TaperFraction float64 // taper fraction of the tukey window
+	PadToPowerOf2 bool    // zero-pad the signal to the next power of two
+}
+
+// ComplexSpectrum is the one-sided Fourier spectrum of a real signal scaled by the time step, so that the
+// coefficients approximate the continuous Fourier transform (units of the signal times seconds).
+type ComplexSpectrum struct {
+	Frequencies  []float64    // Hz, from 0 to the Nyquist frequency
+	Coefficients []complex128 // complex Fourier coefficients
+	Amplitudes   []float64    // modulus of the coefficients
+	Phases       []float64    // phase angle of the coefficients in radians
+	TimeStep     float64      // time step of the transformed signal
+	NumSamples   int          // number of samples of the original signal
+	NumFFT       int          // number of samples after zero-padding
+	AccUnit      string       // unit of the transformed signal if it is an acceleration
+}
+
+func nextPowerOf2(n int) int {
+	power := 1
+	for power < n {
+		power *= 2
+	}
+	return power
+}
+
+// ComplexFourierSpectrum returns the complex Fourier spectrum of the data with proper time step scaling.
+func ComplexFourierSpectrum(data []float64, timeStep float64, options SpectrumOptions) (*ComplexSpectrum, error) {
+	if len(data) == 0 {
+		return nil, errors.New("signal is empty")
+	}
+	if timeStep <= 0 {
+		return nil, errors.New("time step must be a positive number")
+	}
+	window, err := GetWindow(options.Window, len(data), options.TaperFraction)
+	if err != nil {
+		return nil, err
+	}
+
+	numFFT := len(data)
+	if options.PadToPowerOf2 {
+		numFFT = nextPowerOf2(len(data))
+	}
+	signal := make([]float64, numFFT)
+	for i, value := range data {
+		signal[i] = value * window[i]
+	}
+
+	transform := fft.RFFT(signal)
+	numFrequencies := numFFT/2 + 1
+	spectrum := ComplexSpectrum{
+		Frequencies:  make([]float64, numFrequencies),
+		Coefficients: make([]complex128, numFrequencies),
+		Amplitudes:   make([]float64, numFrequencies),
+		Phases:       make([]float64, numFrequencies),
+		TimeStep:     timeStep,
+		NumSamples:   len(data),
+		NumFFT:       numFFT,
+	}
+	for k := 0; k < numFrequencies; k++ {
+		coefficient := transform[k] * complex(timeStep, 0)
+		spectrum.Frequencies[k] = float64(k) / (float64(numFFT) * timeStep)
+		spectrum.Coefficients[k] = coefficient
+		spectrum.Amplitudes[k] = cmplx.Abs(coefficient)
+		spectrum.Phases[k] = cmplx.Phase(coefficient)
+	}
+	return &spectrum, nil
+}
+
+// MotionFourierSpectrum returns the complex Fourier spectrum of the motion accelerations (in g) converted to cm/s2,
+// so that the amplitudes are in cm/s.
+func MotionFourierSpectrum(motion ts.MotionData, options SpectrumOptions) (*ComplexSpectrum, error) {
+	accelerations := make([]float64, len(motion.Accelerations))
+	for i, acc := range motion.Accelerations {
+		accelerations[i] = acc * 981
+	}
+	spectrum, err := ComplexFourierSpectrum(accelerations, motion.TimeStep, options)
+	if err != nil {
+		return nil, err
+	}
+	spectrum.AccUnit = "cm/s2"
+	return spectrum, nil
+}
+
+// InverseTransform returns the time signal of the spectrum truncated to the original number of samples.
+// Any taper window applied to compute the spectrum remains in the signal.
+func (cs *ComplexSpectrum) InverseTransform() []float64 {
+	half := make([]complex128, len(cs.Coefficients))
+	for k, coefficient := range cs.Coefficients {
+		half[k] = coefficient / complex(cs.TimeStep, 0)
+	}
+	return fft.IRFFT(half, cs.NumFFT)[:cs.NumSamples]
+}
+
+// ToMotion returns the inverse transform of a spectrum computed by MotionFourierSpectrum as motion data with
+// accelerations in g and the integrated velocities and displacements.
+func (cs *ComplexSpectrum) ToMotion() (ts.MotionData, error) {
+	if cs.AccUnit != "cm/s2" {
+		return ts.MotionData{}, errors.New("spectrum is not an acceleration spectrum in cm/s2")
+	}
+	accelerations := cs.InverseTransform()
+	for i := range accelerations {
+		accelerations[i] /= 981
+	}
+	motion := ts.MotionData{
+		Accelerations: accelerations,
+		TimeStep:      cs.TimeStep,
+		AccUnit:       "g",
+		VelUnit:       "cm/s",
+		DispUnit:      "cm",
+	}
+	motion.FromAcceleration()
+	return motion, nil
+}
+
+// PhaseSpectrum returns the unwrapped phase angles of the spectrum in radians.
+func (cs *ComplexSpectrum) PhaseSpectrum() []float64 {
+	unwrapped := make([]float64, len(cs.Phases))
+	var offset float64
+	for k, phase := range cs.Phases {
+		if k > 0 {
+			delta := phase - cs.Phases[k-1]
+			if delta > math.Pi {
+				offset -= 2 * math.Pi
+			} else if delta < -math.Pi {
+				offset += 2 * math.Pi
+			}
+		}
+		unwrapped[k] = phase + offset
+	}
+	return unwrapped
+}
@@ -0,0 +1,89 @@
+package fourier_spectrum
+
+import (
+	td "github.com/geoport/GoQuakeLib/TestData"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	"math"
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func TestComplexFourierSpectrum(t *testing.T) {
+	// a 2 Hz cosine of unit amplitude lasting 10 s has a Fourier amplitude of 5 at 2 Hz
+	timeStep := 0.01
+	data := make([]float64, 1000)
+	for i := range data {
+		data[i] = math.Cos(2 * math.Pi * 2 * float64(i) * timeStep)
+	}
+	spectrum, err := ComplexFourierSpectrum(data, timeStep, SpectrumOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	index := np.ArgMax(spectrum.Amplitudes)
+	if spectrum.Frequencies[index] != 2 || np.Round(spectrum.Amplitudes[index], 5) != 5. {
+		t.Errorf(
+			"Expected amplitude 5 at 2 Hz, got %f at %f Hz", spectrum.Amplitudes[index], spectrum.Frequencies[index],
+		)
+	}
+	if np.Round(spectrum.Phases[index], 5) != 0. {
+		t.Errorf("Expected zero phase for a cosine, got %f", spectrum.Phases[index])
+	}
+
+	padded, _ := ComplexFourierSpectrum(data, timeStep, SpectrumOptions{Window: "hann", PadToPowerOf2: true})
+	if padded.NumFFT != 1024 || len(padded.Frequencies) != 513 {
+		t.Errorf("Expected 1024 point FFT with 513 frequencies, got %d and %d", padded.NumFFT, len(padded.Frequencies))
+	}
+
+	if _, err = ComplexFourierSpectrum(data, 0, SpectrumOptions{}); err == nil {
+		t.Errorf("Expected error for zero time step")
+	}
+}
+
+func TestComplexSpectrum_InverseTransform(t *testing.T) {
+	acc := td.TestMotion["Accelerations"].([]float64)
+	timeStep := td.TestMotion["TimeStep"].(float64)
+	for _, pad := range []bool{false, true} {
+		spectrum, _ := ComplexFourierSpectrum(acc, timeStep, SpectrumOptions{PadToPowerOf2: pad})
+		signal := spectrum.InverseTransform()
+		if !np.AllClose(signal, acc, 1e-12) {
+			t.Errorf("Expected inverse transform to recover the signal (padding %v)", pad)
+		}
+	}
+}
+
+func TestComplexSpectrum_ToMotion(t *testing.T) {
+	motion := ts.MotionData{
+		Accelerations: td.TestMotion["Accelerations"].([]float64),
+		TimeStep:      td.TestMotion["TimeStep"].(float64),
+		AccUnit:       "g",
+	}
+	spectrum, _ := MotionFourierSpectrum(motion, SpectrumOptions{})
+	recovered, err := spectrum.ToMotion()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !np.AllClose(recovered.Accelerations, motion.Accelerations, 1e-12) {
+		t.Errorf("Expected recovered accelerations in g to match the original record")
+	}
+	original := motion
+	original.Accelerations = append([]float64{}, motion.Accelerations...)
+	original.FromAcceleration()
+	if !np.AllClose(recovered.Velocities, original.Velocities, 1e-9) {
+		t.Errorf("Expected recovered velocities to match the integrated original record")
+	}
+}
+
+func TestComplexSpectrum_PhaseSpectrum(t *testing.T) {
+	// a delayed impulse has a linear phase spectrum
+	data := make([]float64, 64)
+	data[3] = 1
+	spectrum, _ := ComplexFourierSpectrum(data, 1, SpectrumOptions{})
+	phases := spectrum.PhaseSpectrum()
+	for k, phase := range phases {
+		expected := -2 * math.Pi * spectrum.Frequencies[k] * 3
+		if math.Abs(phase-expected) > 1e-9 {
+			t.Errorf("Expected phase %f at index %d, got %f", expected, k, phase)
+		}
+	}
+}
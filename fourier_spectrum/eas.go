@@ -0,0 +1,68 @@
+package fourier_spectrum
+
+import (
+	"errors"
+	"math"
+
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// Konno-Ohmachi bandwidth coefficient used for the NGA-West2 effective amplitude spectrum
+const easBandwidth = 188.
+
+// NGAWest2Frequencies returns the 381 frequencies, log-spaced between 0.01 and 100 Hz, at which the NGA-West2
+// Fourier amplitude spectra are reported.
+func NGAWest2Frequencies() []float64 {
+	return LogSpacedFrequencies(0.01, 100, 381)
+}
+
+func padMotion(motion ts.MotionData, length int) ts.MotionData {
+	accelerations := make([]float64, length)
+	copy(accelerations, motion.Accelerations)
+	motion.Accelerations = accelerations
+	return motion
+}
+
+// EffectiveAmplitudeSpectrum returns the frequencies and the effective amplitude spectrum (cm/s) of two horizontal
+// components as defined for the NGA-West2 FAS models: the root-mean-square of the two Fourier amplitude spectra
+// smoothed with the Konno-Ohmachi window (b = 188). The spectrum is evaluated at the NGA-West2 frequencies between
+// the lowest non-zero frequency of the record and the Nyquist frequency.
+func EffectiveAmplitudeSpectrum(motion1, motion2 ts.MotionData) ([]float64, []float64, error) {
+	if motion1.TimeStep != motion2.TimeStep {
+		return nil, nil, errors.New("components must have the same time step")
+	}
+	length := int(math.Max(float64(len(motion1.Accelerations)), float64(len(motion2.Accelerations))))
+
+	spectrum1, err := MotionFourierSpectrum(padMotion(motion1, length), SpectrumOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	spectrum2, err := MotionFourierSpectrum(padMotion(motion2, length), SpectrumOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rms := make([]float64, len(spectrum1.Amplitudes))
+	for i := range rms {
+		rms[i] = math.Sqrt((math.Pow(spectrum1.Amplitudes[i], 2) + math.Pow(spectrum2.Amplitudes[i], 2)) / 2)
+	}
+
+	frequencies := spectrum1.Frequencies
+	minFrequency := frequencies[1]
+	maxFrequency := frequencies[len(frequencies)-1]
+	var outputFrequencies []float64
+	for _, f := range NGAWest2Frequencies() {
+		if f >= minFrequency && f <= maxFrequency {
+			outputFrequencies = append(outputFrequencies, f)
+		}
+	}
+	if len(outputFrequencies) == 0 {
+		return nil, nil, errors.New("record is too short to evaluate the effective amplitude spectrum")
+	}
+
+	eas, err := KonnoOhmachiSmoothing(frequencies, rms, outputFrequencies, easBandwidth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return outputFrequencies, eas, nil
+}
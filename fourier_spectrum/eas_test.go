@@ -0,0 +1,52 @@
+package fourier_spectrum
+
+import (
+	td "github.com/geoport/GoQuakeLib/TestData"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	"math"
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func TestNGAWest2Frequencies(t *testing.T) {
+	frequencies := NGAWest2Frequencies()
+	if len(frequencies) != 381 || np.Round(frequencies[0], 5) != 0.01 || np.Round(frequencies[380], 5) != 100. {
+		t.Errorf("Expected 381 frequencies between 0.01 and 100 Hz, got %d", len(frequencies))
+	}
+}
+
+func TestEffectiveAmplitudeSpectrum(t *testing.T) {
+	acc := td.TestMotion["Accelerations"].([]float64)
+	timeStep := td.TestMotion["TimeStep"].(float64)
+	motion := ts.MotionData{Accelerations: acc, TimeStep: timeStep}
+
+	// identical components give the smoothed FAS of one component
+	frequencies, eas, err := EffectiveAmplitudeSpectrum(motion, motion)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	spectrum, _ := MotionFourierSpectrum(motion, SpectrumOptions{})
+	expected, _ := KonnoOhmachiSmoothing(spectrum.Frequencies, spectrum.Amplitudes, frequencies, 188)
+	if !np.AllClose(eas, expected, 1e-12) {
+		t.Errorf("Expected EAS of identical components to equal their smoothed FAS")
+	}
+	if frequencies[0] < spectrum.Frequencies[1] || frequencies[len(frequencies)-1] > 10 {
+		t.Errorf("Expected frequencies within the usable band, got %f-%f", frequencies[0], frequencies[len(frequencies)-1])
+	}
+
+	// a zero component scales the spectrum by 1/sqrt(2)
+	zero := ts.MotionData{Accelerations: make([]float64, len(acc)), TimeStep: timeStep}
+	_, easZero, _ := EffectiveAmplitudeSpectrum(motion, zero)
+	for i := range easZero {
+		if math.Abs(easZero[i]*math.Sqrt2-eas[i]) > 1e-9*eas[i] {
+			t.Errorf("Expected EAS with a zero component to be FAS/sqrt(2) at %f Hz", frequencies[i])
+			break
+		}
+	}
+
+	zero.TimeStep = 0.01
+	if _, _, err = EffectiveAmplitudeSpectrum(motion, zero); err == nil {
+		t.Errorf("Expected error for different time steps")
+	}
+}
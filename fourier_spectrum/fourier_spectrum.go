@@ -2,18 +2,23 @@ package fourier_spectrum
 
 import (
 	"math"
+	"math/cmplx"
 
-	"github.com/eripe970/go-dsp-utils"
+	"github.com/geoport/GoQuakeLib/fft"
 	np "github.com/geoport/numpy4go/vectors"
 )
 
 func FourierSpectrum(data []float64, timeStep float64) ([]float64, []float64, []float64) {
-	signal := dsp.Signal{SampleRate: 1 / timeStep, Signal: data}
-	spectrum, _ := signal.FrequencySpectrum()
-	frequency := spectrum.Frequencies
+	transform := fft.RFFT(data)
+	length := float64(len(data))
+	frequency := make([]float64, len(data)/2)
+	fourierAmplitudes := make([]float64, len(data)/2)
+	for i := range frequency {
+		frequency[i] = float64(i) / (length * timeStep)
+		fourierAmplitudes[i] = cmplx.Abs(transform[i]) / length * 2
+	}
 
-	fourierAmplitudes := spectrum.Spectrum
-	T := float64(len(data)) * timeStep
+	T := length * timeStep
 	aRMS2 := np.DividedBy(np.Cumtrapz(np.Pow(data, 2), timeStep, 0), T)
 	aRMS := math.Pow(aRMS2[len(aRMS2)-1], 0.5)
 	powerAmplitudes := np.DividedBy(np.Pow(fourierAmplitudes, 2), math.Pi*T*math.Pow(aRMS, 2))
@@ -59,3 +59,50 @@ func TestFourierSpectrum(t *testing.T) {
 	}
 
 }
+
+// TestFourierSpectrumBaseline pins the amplitudes |X|*2/n of the go-dsp FrequencySpectrum the spectrum was computed
+// with before the native FFT.
+func TestFourierSpectrumBaseline(t *testing.T) {
+	acc := td.TestMotion["Accelerations"].([]float64)
+	timeStep := td.TestMotion["TimeStep"].(float64)
+	frequencies, fourierAmplitudes, _ := FourierSpectrum(acc, timeStep)
+	if len(fourierAmplitudes) != 2546 || len(frequencies) != 2546 {
+		t.Fatalf("Expected 2546 frequencies, got %d", len(fourierAmplitudes))
+	}
+	for _, test := range []struct {
+		index     int
+		amplitude float64
+	}{
+		{0, 3.879532691923e-08},
+		{101, 3.159192265408e-04},
+		{202, 4.026463952925e-04},
+		{303, 4.977232199328e-04},
+		{404, 4.610103655188e-04},
+		{505, 2.934751377765e-04},
+		{606, 2.877495467059e-04},
+		{707, 4.129677711999e-04},
+		{808, 2.284479978833e-04},
+		{909, 1.859216536264e-04},
+		{1010, 9.117056470316e-05},
+		{1111, 4.002947030585e-05},
+		{1212, 1.711031299985e-05},
+		{1313, 1.360102610598e-05},
+		{1414, 7.647319528458e-07},
+		{1515, 7.351634151475e-06},
+		{1616, 8.208314592444e-06},
+		{1717, 9.471839382094e-06},
+		{1818, 2.310936314827e-06},
+		{1919, 1.173497482642e-06},
+		{2020, 1.288827890115e-06},
+		{2121, 1.725995555084e-06},
+		{2222, 2.924043224045e-06},
+		{2323, 1.957410782541e-06},
+		{2424, 9.767506233240e-07},
+		{2525, 4.897140578253e-07},
+		{126, 1.290292426633e-03},
+	} {
+		if actual := fourierAmplitudes[test.index]; math.Abs(actual-test.amplitude) > 1e-9*test.amplitude {
+			t.Errorf("Expected amplitude %e at index %d, got %e", test.amplitude, test.index, actual)
+		}
+	}
+}
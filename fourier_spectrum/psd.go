@@ -0,0 +1,392 @@
+package fourier_spectrum
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+
+	"github.com/geoport/GoQuakeLib/fft"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// WelchOptions controls the segmentation of Welch's method.
+type WelchOptions struct {
+	SegmentLength   int     // number of samples in each segment, clipped to the record length
+	Overlap         float64 // overlap between consecutive segments as a fraction of the segment length
+	Window          string  // taper window applied to each segment, see GetWindow
+	TaperFraction   float64 // fraction of the "tukey" window inside the cosine tapers, see GetWindow
+	Detrend         string  // "none", "constant" (remove the mean) or "linear" (remove the best-fit line)
+	ConfidenceLevel float64 // confidence level of the intervals, e.g. 0.95
+}
+
+// PSDData holds a one-sided power spectral density estimate.
+type PSDData struct {
+	Frequencies      []float64 // Hz
+	Densities        []float64 // power spectral density, (signal unit)^2/Hz
+	LowerBounds      []float64 // lower confidence bound of the densities
+	UpperBounds      []float64 // upper confidence bound of the densities
+	NumSegments      int       // number of averaged segments
+	DegreesOfFreedom float64   // equivalent degrees of freedom of the estimate
+}
+
+// CrossSpectrumData holds a one-sided cross-spectral density estimate.
+type CrossSpectrumData struct {
+	Frequencies []float64
+	Densities   []complex128 // cross-spectral density of the first and second channel
+	Magnitudes  []float64    // modulus of the densities
+	Phases      []float64    // phase of the densities in radians
+	// LowerBounds and UpperBounds are the confidence bounds of the magnitudes from their normalized random error
+	// 1/(|coherence| sqrt(nd)), with nd the equivalent number of independent segments (Bendat and Piersol, 2010,
+	// Table 9.1). The normal approximation holds where the error is small; the upper bound is infinite where the
+	// channels are incoherent.
+	LowerBounds      []float64
+	UpperBounds      []float64
+	NumSegments      int
+	DegreesOfFreedom float64
+}
+
+// WelchSpectraData holds the one-sided auto- and cross-spectral densities of two channels.
+type WelchSpectraData struct {
+	Frequencies      []float64
+	Densities1       []float64    // power spectral density of the first channel
+	Densities2       []float64    // power spectral density of the second channel
+	CrossDensities   []complex128 // cross-spectral density of the first and second channel
+	NumSegments      int
+	DegreesOfFreedom float64
+}
+
+// CoherenceData holds the magnitude-squared coherence of two channels.
+type CoherenceData struct {
+	Frequencies       []float64
+	Coherences        []float64 // magnitude-squared coherence between 0 and 1
+	SignificanceLevel float64   // coherence below this level is not significantly different from zero
+	NumSegments       int
+}
+
+// DefaultWelchOptions returns 50% overlapping hann windowed segments of 256 samples with the mean removed. A "tukey"
+// window tapers 10% of the segments.
+func DefaultWelchOptions() WelchOptions {
+	return WelchOptions{
+		SegmentLength:   256,
+		Overlap:         0.5,
+		Window:          "hann",
+		TaperFraction:   0.1,
+		Detrend:         "constant",
+		ConfidenceLevel: 0.95,
+	}
+}
+
+func detrend(segment []float64, method string) ([]float64, error) {
+	detrended := make([]float64, len(segment))
+	copy(detrended, segment)
+	switch method {
+	case "", "none":
+	case "constant":
+		var mean float64
+		for _, value := range segment {
+			mean += value
+		}
+		mean /= float64(len(segment))
+		for i := range detrended {
+			detrended[i] -= mean
+		}
+	case "linear":
+		n := float64(len(segment))
+		var sumX, sumY, sumXY, sumXX float64
+		for i, value := range segment {
+			x := float64(i)
+			sumX += x
+			sumY += value
+			sumXY += x * value
+			sumXX += x * x
+		}
+		slope := 0.
+		if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+			slope = (n*sumXY - sumX*sumY) / denominator
+		}
+		intercept := (sumY - slope*sumX) / n
+		for i := range detrended {
+			detrended[i] -= intercept + slope*float64(i)
+		}
+	default:
+		return nil, errors.New("detrend method not supported")
+	}
+	return detrended, nil
+}
+
+// welchEstimate holds the averaged one-sided cross spectra of the channel pairs.
+type welchEstimate struct {
+	frequencies      []float64
+	pxx, pyy         []float64
+	pxy              []complex128
+	numSegments      int
+	degreesOfFreedom float64
+}
+
+func welch(x, y []float64, timeStep float64, options WelchOptions) (*welchEstimate, error) {
+	if len(x) == 0 {
+		return nil, errors.New("signal is empty")
+	}
+	if len(x) != len(y) {
+		return nil, errors.New("channels must be of equal length")
+	}
+	if timeStep <= 0 {
+		return nil, errors.New("time step must be a positive number")
+	}
+	if options.SegmentLength < 2 {
+		return nil, errors.New("segment length must be at least 2")
+	}
+	if options.Overlap < 0 || options.Overlap >= 1 {
+		return nil, errors.New("overlap must be between 0 and 1")
+	}
+
+	segmentLength := options.SegmentLength
+	if segmentLength > len(x) {
+		segmentLength = len(x)
+	}
+	step := segmentLength - int(math.Round(options.Overlap*float64(segmentLength)))
+	if step < 1 {
+		step = 1
+	}
+	window, err := GetWindow(options.Window, segmentLength, options.TaperFraction)
+	if err != nil {
+		return nil, err
+	}
+	var windowPower float64
+	for _, w := range window {
+		windowPower += w * w
+	}
+
+	numFrequencies := segmentLength/2 + 1
+	estimate := welchEstimate{
+		frequencies: make([]float64, numFrequencies),
+		pxx:         make([]float64, numFrequencies),
+		pyy:         make([]float64, numFrequencies),
+		pxy:         make([]complex128, numFrequencies),
+	}
+	for k := range estimate.frequencies {
+		estimate.frequencies[k] = float64(k) / (float64(segmentLength) * timeStep)
+	}
+
+	plan, err := fft.NewRealPlan(segmentLength)
+	if err != nil {
+		return nil, err
+	}
+	X := make([]complex128, numFrequencies)
+	Y := make([]complex128, numFrequencies)
+	transformSegment := func(signal []float64, transform []complex128) error {
+		segment, err := detrend(signal, options.Detrend)
+		if err != nil {
+			return err
+		}
+		for i := range segment {
+			segment[i] *= window[i]
+		}
+		plan.Forward(transform, segment)
+		return nil
+	}
+
+	for start := 0; start+segmentLength <= len(x); start += step {
+		if err = transformSegment(x[start:start+segmentLength], X); err != nil {
+			return nil, err
+		}
+		if err = transformSegment(y[start:start+segmentLength], Y); err != nil {
+			return nil, err
+		}
+		for k := 0; k < numFrequencies; k++ {
+			estimate.pxx[k] += real(X[k] * cmplx.Conj(X[k]))
+			estimate.pyy[k] += real(Y[k] * cmplx.Conj(Y[k]))
+			estimate.pxy[k] += cmplx.Conj(X[k]) * Y[k]
+		}
+		estimate.numSegments++
+	}
+
+	// density scaling of the one-sided spectrum, only DC and Nyquist are not doubled
+	for k := 0; k < numFrequencies; k++ {
+		scale := 2 * timeStep / (windowPower * float64(estimate.numSegments))
+		if k == 0 || (segmentLength%2 == 0 && k == numFrequencies-1) {
+			scale /= 2
+		}
+		estimate.pxx[k] *= scale
+		estimate.pyy[k] *= scale
+		estimate.pxy[k] *= complex(scale, 0)
+	}
+	estimate.degreesOfFreedom = welchDegreesOfFreedom(window, step, estimate.numSegments)
+
+	return &estimate, nil
+}
+
+// welchDegreesOfFreedom returns the equivalent degrees of freedom of Welch's estimate accounting for the
+// correlation between overlapping segments (Percival & Walden, 1993).
+func welchDegreesOfFreedom(window []float64, step, numSegments int) float64 {
+	var windowPower float64
+	for _, w := range window {
+		windowPower += w * w
+	}
+	denominator := 1.
+	for j := 1; j < numSegments; j++ {
+		shift := j * step
+		if shift >= len(window) {
+			break
+		}
+		var overlap float64
+		for n := 0; n+shift < len(window); n++ {
+			overlap += window[n] * window[n+shift]
+		}
+		rho := math.Pow(overlap/windowPower, 2)
+		denominator += 2 * (1 - float64(j)/float64(numSegments)) * rho
+	}
+	return 2 * float64(numSegments) / denominator
+}
+
+// chiSquareQuantile returns the p quantile of the chi-square distribution with dof degrees of freedom using the
+// Wilson-Hilferty approximation.
+func chiSquareQuantile(p, dof float64) float64 {
+	z := math.Sqrt2 * math.Erfinv(2*p-1)
+	h := 2 / (9 * dof)
+	return dof * math.Pow(math.Max(1-h+z*math.Sqrt(h), 0), 3)
+}
+
+func confidenceBounds(values []float64, dof, level float64) ([]float64, []float64) {
+	alpha := 1 - level
+	lowerFactor := dof / chiSquareQuantile(1-alpha/2, dof)
+	upperFactor := dof / chiSquareQuantile(alpha/2, dof)
+	lower := make([]float64, len(values))
+	upper := make([]float64, len(values))
+	for i, value := range values {
+		lower[i] = value * lowerFactor
+		upper[i] = value * upperFactor
+	}
+	return lower, upper
+}
+
+// crossSpectrumBounds returns the confidence bounds of the cross-spectral magnitudes from their normalized random
+// error 1/(|coherence| sqrt(nd)), with nd = dof/2 independent segments.
+func crossSpectrumBounds(estimate *welchEstimate, magnitudes []float64, level float64) ([]float64, []float64) {
+	z := math.Sqrt2 * math.Erfinv(level)
+	segments := estimate.degreesOfFreedom / 2
+	lower := make([]float64, len(magnitudes))
+	upper := make([]float64, len(magnitudes))
+	for k, coherence := range coherenceOf(estimate) {
+		if coherence == 0 {
+			upper[k] = math.Inf(1)
+			continue
+		}
+		randomError := 1 / math.Sqrt(coherence*segments)
+		lower[k] = magnitudes[k] * math.Max(1-z*randomError, 0)
+		upper[k] = magnitudes[k] * (1 + z*randomError)
+	}
+	return lower, upper
+}
+
+func checkConfidenceLevel(level float64) error {
+	if level <= 0 || level >= 1 {
+		return errors.New("confidence level must be between 0 and 1")
+	}
+	return nil
+}
+
+// WelchPSD returns the power spectral density of the motion accelerations estimated with Welch's method.
+func WelchPSD(motion ts.MotionData, options WelchOptions) (*PSDData, error) {
+	if err := checkConfidenceLevel(options.ConfidenceLevel); err != nil {
+		return nil, err
+	}
+	estimate, err := welch(motion.Accelerations, motion.Accelerations, motion.TimeStep, options)
+	if err != nil {
+		return nil, err
+	}
+	psd := PSDData{
+		Frequencies:      estimate.frequencies,
+		Densities:        estimate.pxx,
+		NumSegments:      estimate.numSegments,
+		DegreesOfFreedom: estimate.degreesOfFreedom,
+	}
+	psd.LowerBounds, psd.UpperBounds = confidenceBounds(psd.Densities, psd.DegreesOfFreedom, options.ConfidenceLevel)
+	return &psd, nil
+}
+
+// CrossSpectralDensity returns the cross-spectral density of the accelerations of two motions estimated with
+// Welch's method.
+func CrossSpectralDensity(motion1, motion2 ts.MotionData, options WelchOptions) (*CrossSpectrumData, error) {
+	if err := checkConfidenceLevel(options.ConfidenceLevel); err != nil {
+		return nil, err
+	}
+	if motion1.TimeStep != motion2.TimeStep {
+		return nil, errors.New("channels must have the same time step")
+	}
+	estimate, err := welch(motion1.Accelerations, motion2.Accelerations, motion1.TimeStep, options)
+	if err != nil {
+		return nil, err
+	}
+	csd := CrossSpectrumData{
+		Frequencies:      estimate.frequencies,
+		Densities:        estimate.pxy,
+		Magnitudes:       make([]float64, len(estimate.pxy)),
+		Phases:           make([]float64, len(estimate.pxy)),
+		NumSegments:      estimate.numSegments,
+		DegreesOfFreedom: estimate.degreesOfFreedom,
+	}
+	for k, density := range estimate.pxy {
+		csd.Magnitudes[k] = cmplx.Abs(density)
+		csd.Phases[k] = cmplx.Phase(density)
+	}
+	csd.LowerBounds, csd.UpperBounds = crossSpectrumBounds(estimate, csd.Magnitudes, options.ConfidenceLevel)
+	return &csd, nil
+}
+
+// WelchSpectra returns the auto- and cross-spectral densities of the accelerations of two motions estimated with
+// Welch's method in a single pass over the segments.
+func WelchSpectra(motion1, motion2 ts.MotionData, options WelchOptions) (*WelchSpectraData, error) {
+	if motion1.TimeStep != motion2.TimeStep {
+		return nil, errors.New("channels must have the same time step")
+	}
+	estimate, err := welch(motion1.Accelerations, motion2.Accelerations, motion1.TimeStep, options)
+	if err != nil {
+		return nil, err
+	}
+	return &WelchSpectraData{
+		Frequencies:      estimate.frequencies,
+		Densities1:       estimate.pxx,
+		Densities2:       estimate.pyy,
+		CrossDensities:   estimate.pxy,
+		NumSegments:      estimate.numSegments,
+		DegreesOfFreedom: estimate.degreesOfFreedom,
+	}, nil
+}
+
+// Coherence returns the magnitude-squared coherence of the accelerations of two motions estimated with Welch's
+// method.
+func Coherence(motion1, motion2 ts.MotionData, options WelchOptions) (*CoherenceData, error) {
+	if err := checkConfidenceLevel(options.ConfidenceLevel); err != nil {
+		return nil, err
+	}
+	if motion1.TimeStep != motion2.TimeStep {
+		return nil, errors.New("channels must have the same time step")
+	}
+	estimate, err := welch(motion1.Accelerations, motion2.Accelerations, motion1.TimeStep, options)
+	if err != nil {
+		return nil, err
+	}
+	coherence := CoherenceData{
+		Frequencies: estimate.frequencies,
+		Coherences:  coherenceOf(estimate),
+		NumSegments: estimate.numSegments,
+	}
+	if estimate.numSegments > 1 {
+		coherence.SignificanceLevel = 1 - math.Pow(1-options.ConfidenceLevel, 1/float64(estimate.numSegments-1))
+	} else {
+		coherence.SignificanceLevel = 1
+	}
+	return &coherence, nil
+}
+
+func coherenceOf(estimate *welchEstimate) []float64 {
+	coherences := make([]float64, len(estimate.pxy))
+	for k, pxy := range estimate.pxy {
+		denominator := estimate.pxx[k] * estimate.pyy[k]
+		if denominator > 0 {
+			coherences[k] = math.Pow(cmplx.Abs(pxy), 2) / denominator
+		}
+	}
+	return coherences
+}
@@ -0,0 +1,185 @@
+package fourier_spectrum
+
+import (
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	"math"
+	"math/rand"
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func whiteNoise(length int, seed int64) []float64 {
+	random := rand.New(rand.NewSource(seed))
+	noise := make([]float64, length)
+	for i := range noise {
+		noise[i] = random.NormFloat64()
+	}
+	return noise
+}
+
+func TestWelchPSD(t *testing.T) {
+	// white noise has a one-sided density of 2*variance*dt
+	timeStep := 0.01
+	motion := ts.MotionData{Accelerations: whiteNoise(20000, 1), TimeStep: timeStep}
+	psd, err := WelchPSD(motion, DefaultWelchOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	variance := math.Pow(np.StandardDeviation(motion.Accelerations), 2)
+	mean := np.Mean(psd.Densities[1 : len(psd.Densities)-1])
+	if math.Abs(mean-2*variance*timeStep)/(2*variance*timeStep) > 0.01 {
+		t.Errorf("Expected mean density %f, got %f", 2*variance*timeStep, mean)
+	}
+	if psd.NumSegments != 155 {
+		t.Errorf("Expected 155 segments, got %d", psd.NumSegments)
+	}
+	// 50% overlapping hann windows give about 1.9 times the segments count in degrees of freedom
+	if np.Round(psd.DegreesOfFreedom/float64(psd.NumSegments), 1) != 1.9 {
+		t.Errorf("Expected degrees of freedom ratio 1.89, got %f", psd.DegreesOfFreedom/float64(psd.NumSegments))
+	}
+	for k := range psd.Densities {
+		if psd.LowerBounds[k] > psd.Densities[k] || psd.UpperBounds[k] < psd.Densities[k] {
+			t.Errorf("Expected density within its confidence bounds at index %d", k)
+			break
+		}
+	}
+
+	options := DefaultWelchOptions()
+	options.Overlap = 1
+	if _, err = WelchPSD(motion, options); err == nil {
+		t.Errorf("Expected error for overlap of 1")
+	}
+}
+
+func TestCrossSpectralDensity(t *testing.T) {
+	timeStep := 0.01
+	motion := ts.MotionData{Accelerations: whiteNoise(4096, 2), TimeStep: timeStep}
+	psd, _ := WelchPSD(motion, DefaultWelchOptions())
+	csd, err := CrossSpectralDensity(motion, motion, DefaultWelchOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !np.AllClose(csd.Magnitudes, psd.Densities, 1e-15) || np.Max(np.Abs(csd.Phases)) != 0 {
+		t.Errorf("Expected the cross spectrum of a signal with itself to equal its PSD")
+	}
+	// coherent channels have the random error 1/sqrt(nd)
+	randomError := 1.959964 / math.Sqrt(csd.DegreesOfFreedom/2)
+	for k, magnitude := range csd.Magnitudes {
+		lower, upper := magnitude*(1-randomError), magnitude*(1+randomError)
+		if math.Abs(csd.LowerBounds[k]-lower) > 1e-6*magnitude || math.Abs(csd.UpperBounds[k]-upper) > 1e-6*magnitude {
+			t.Errorf("Expected bounds %f and %f at index %d, got %f and %f",
+				lower, upper, k, csd.LowerBounds[k], csd.UpperBounds[k])
+			break
+		}
+	}
+
+	// incoherent channels have wider bounds
+	other := ts.MotionData{Accelerations: whiteNoise(4096, 3), TimeStep: timeStep}
+	incoherent, _ := CrossSpectralDensity(motion, other, DefaultWelchOptions())
+	for k, magnitude := range incoherent.Magnitudes {
+		if incoherent.UpperBounds[k]/magnitude <= 1+randomError {
+			t.Errorf("Expected wider bounds of incoherent channels at index %d", k)
+			break
+		}
+	}
+}
+
+func TestWelchTaper(t *testing.T) {
+	motion := ts.MotionData{Accelerations: whiteNoise(4096, 5), TimeStep: 0.01}
+	boxcar := DefaultWelchOptions()
+	boxcar.Window = "boxcar"
+	tukey := DefaultWelchOptions()
+	tukey.Window, tukey.TaperFraction = "tukey", 0
+	expected, _ := WelchPSD(motion, boxcar)
+	psd, err := WelchPSD(motion, tukey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !np.AllClose(psd.Densities, expected.Densities, 1e-12) {
+		t.Errorf("Expected an untapered tukey window to equal the boxcar window")
+	}
+	tukey.TaperFraction = 0.5
+	if psd, _ = WelchPSD(motion, tukey); np.AllClose(psd.Densities, expected.Densities, 1e-6) {
+		t.Errorf("Expected the taper fraction to change the estimate")
+	}
+	tukey.TaperFraction = 2
+	if _, err = WelchPSD(motion, tukey); err == nil {
+		t.Errorf("Expected error for a taper fraction above 1")
+	}
+}
+
+func TestCoherence(t *testing.T) {
+	timeStep := 0.01
+	noise1 := whiteNoise(8192, 3)
+	noise2 := whiteNoise(8192, 4)
+	mixed := make([]float64, len(noise1))
+	for i := range mixed {
+		mixed[i] = noise1[i] + noise2[i]
+	}
+	motion1 := ts.MotionData{Accelerations: noise1, TimeStep: timeStep}
+	motion2 := ts.MotionData{Accelerations: mixed, TimeStep: timeStep}
+
+	coherence, err := Coherence(motion1, motion1, DefaultWelchOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !np.AllClose(coherence.Coherences, np.Ones(len(coherence.Coherences)), 1e-12) {
+		t.Errorf("Expected unit coherence of a signal with itself")
+	}
+
+	// equal parts of common and independent noise have a coherence of 0.5
+	coherence, _ = Coherence(motion1, motion2, DefaultWelchOptions())
+	if math.Abs(np.Mean(coherence.Coherences)-0.5) > 0.05 {
+		t.Errorf("Expected mean coherence 0.5, got %f", np.Mean(coherence.Coherences))
+	}
+	if coherence.SignificanceLevel <= 0 || coherence.SignificanceLevel >= 0.5 {
+		t.Errorf("Expected significance level between 0 and 0.5, got %f", coherence.SignificanceLevel)
+	}
+}
+
+func TestWelchSpectra(t *testing.T) {
+	motion1 := ts.MotionData{Accelerations: whiteNoise(4096, 6), TimeStep: 0.01}
+	motion2 := ts.MotionData{Accelerations: whiteNoise(4096, 7), TimeStep: 0.01}
+	spectra, err := WelchSpectra(motion1, motion2, DefaultWelchOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	psd1, _ := WelchPSD(motion1, DefaultWelchOptions())
+	psd2, _ := WelchPSD(motion2, DefaultWelchOptions())
+	csd, _ := CrossSpectralDensity(motion1, motion2, DefaultWelchOptions())
+	if !np.AllClose(spectra.Densities1, psd1.Densities, 1e-15) || !np.AllClose(spectra.Densities2, psd2.Densities, 1e-15) {
+		t.Errorf("Expected the auto spectra equal to the PSD of each channel")
+	}
+	for k, density := range spectra.CrossDensities {
+		if density != csd.Densities[k] {
+			t.Errorf("Expected cross spectrum %v at index %d, got %v", csd.Densities[k], k, density)
+			break
+		}
+	}
+	motion2.TimeStep = 0.02
+	if _, err = WelchSpectra(motion1, motion2, DefaultWelchOptions()); err == nil {
+		t.Errorf("Expected error for channels of different time steps")
+	}
+}
+
+func TestChiSquareQuantile(t *testing.T) {
+	if np.Round(chiSquareQuantile(0.975, 10), 1) != 20.5 {
+		t.Errorf("Expected 20.5, got %f", chiSquareQuantile(0.975, 10))
+	}
+	if np.Round(chiSquareQuantile(0.025, 10), 1) != 3.2 {
+		t.Errorf("Expected 3.2, got %f", chiSquareQuantile(0.025, 10))
+	}
+}
+
+func TestDetrend(t *testing.T) {
+	line := []float64{1, 3, 5, 7}
+	detrended, _ := detrend(line, "linear")
+	if !np.AllClose(detrended, make([]float64, 4), 1e-12) {
+		t.Errorf("Expected zero residual of a line, got %v", detrended)
+	}
+	detrended, _ = detrend(line, "constant")
+	if !np.AllClose(detrended, []float64{-3, -1, 1, 3}, 1e-12) {
+		t.Errorf("Expected [-3 -1 1 3], got %v", detrended)
+	}
+}
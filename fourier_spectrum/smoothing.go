@@ -0,0 +1,260 @@
+package fourier_spectrum
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+// number of side lobes of the sinc^4 windows kept on each side of the center frequency
+const windowLobes = 5
+
+// number of bins within the characteristic half width of a window
+const binsPerHalfWidth = 50
+
+// Smoother holds precomputed smoothing weights so that many spectra sampled at the same frequencies can be
+// smoothed without recomputing the window.
+//
+// All supported windows depend only on the distance between frequencies in a transformed coordinate (log10(f) for
+// Konno-Ohmachi and log-window averaging, f for Parzen). Input samples are grouped into narrow bins of that
+// coordinate, which keeps the cost of each output frequency independent of the record length.
+type Smoother struct {
+	Frequencies []float64 // output frequencies
+	binOf       []int
+	numBins     int
+	starts      []int
+	weights     [][]float64
+	nearest     []int
+}
+
+// Apply returns the smoothed amplitudes at the output frequencies of the smoother.
+func (s *Smoother) Apply(amplitudes []float64) ([]float64, error) {
+	if len(amplitudes) != len(s.binOf) {
+		return nil, errors.New("amplitudes must be of the same length as the smoother frequencies")
+	}
+	binSums := make([]float64, s.numBins)
+	for j, bin := range s.binOf {
+		if bin >= 0 {
+			binSums[bin] += amplitudes[j]
+		}
+	}
+
+	smoothed := make([]float64, len(s.Frequencies))
+	for i, weights := range s.weights {
+		if weights == nil {
+			smoothed[i] = amplitudes[s.nearest[i]]
+			continue
+		}
+		sums := binSums[s.starts[i] : s.starts[i]+len(weights)]
+		var total float64
+		for k, w := range weights {
+			total += w * sums[k]
+		}
+		smoothed[i] = total
+	}
+	return smoothed, nil
+}
+
+// kernelFunc returns the window weight for the distance between two frequencies in the transformed coordinate.
+type kernelFunc func(distance float64) float64
+
+func newSmoother(
+	frequencies, outputFrequencies []float64, transform func(float64) float64, kernel kernelFunc,
+	halfWidth, support float64,
+) (*Smoother, error) {
+	if len(frequencies) == 0 {
+		return nil, errors.New("frequencies are empty")
+	}
+	if !sort.Float64sAreSorted(frequencies) {
+		return nil, errors.New("frequencies must be in ascending order")
+	}
+	if outputFrequencies == nil {
+		outputFrequencies = frequencies
+	}
+
+	coordinates := make([]float64, len(frequencies))
+	origin, last := math.Inf(1), math.Inf(-1)
+	for j, f := range frequencies {
+		coordinates[j] = transform(f)
+		if !math.IsNaN(coordinates[j]) && !math.IsInf(coordinates[j], 0) {
+			origin = math.Min(origin, coordinates[j])
+			last = math.Max(last, coordinates[j])
+		}
+	}
+	if math.IsInf(origin, 1) {
+		return nil, errors.New("no valid frequencies to smooth")
+	}
+
+	binWidth := halfWidth / binsPerHalfWidth
+	smoother := Smoother{
+		Frequencies: outputFrequencies,
+		binOf:       make([]int, len(frequencies)),
+		numBins:     int((last-origin)/binWidth) + 1,
+		starts:      make([]int, len(outputFrequencies)),
+		weights:     make([][]float64, len(outputFrequencies)),
+		nearest:     make([]int, len(outputFrequencies)),
+	}
+
+	// count-weighted mean coordinate of the samples in each bin
+	binCenters := make([]float64, smoother.numBins)
+	binCounts := make([]float64, smoother.numBins)
+	for j, x := range coordinates {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			smoother.binOf[j] = -1
+			continue
+		}
+		bin := int((x - origin) / binWidth)
+		smoother.binOf[j] = bin
+		binCenters[bin] += x
+		binCounts[bin]++
+	}
+	for k := range binCenters {
+		if binCounts[k] > 0 {
+			binCenters[k] /= binCounts[k]
+		}
+	}
+
+	for i, fc := range outputFrequencies {
+		xc := transform(fc)
+		if math.IsNaN(xc) || math.IsInf(xc, 0) {
+			smoother.weights[i] = nil
+			smoother.nearest[i] = np.ArgMin(np.Abs(np.SumWith(frequencies, -fc)))
+			continue
+		}
+		start := int(math.Floor((xc - support - origin) / binWidth))
+		end := int(math.Floor((xc+support-origin)/binWidth)) + 1
+		start = int(math.Max(float64(start), 0))
+		end = int(math.Min(float64(end), float64(smoother.numBins)))
+
+		var weights []float64
+		var total float64
+		if start < end {
+			weights = make([]float64, end-start)
+			for k := start; k < end; k++ {
+				if binCounts[k] == 0 {
+					continue
+				}
+				weights[k-start] = kernel(binCenters[k] - xc)
+				total += weights[k-start] * binCounts[k]
+			}
+		}
+		if total == 0 {
+			// the window is narrower than the frequency resolution, fall back to the nearest sample
+			smoother.weights[i] = nil
+			smoother.nearest[i] = np.ArgMin(np.Abs(np.SumWith(frequencies, -fc)))
+			continue
+		}
+		for k := range weights {
+			weights[k] /= total
+		}
+		smoother.starts[i] = start
+		smoother.weights[i] = weights
+	}
+	return &smoother, nil
+}
+
+func sinc4(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Pow(math.Sin(x)/x, 4)
+}
+
+func log10Coordinate(f float64) float64 {
+	if f <= 0 {
+		return math.NaN()
+	}
+	return math.Log10(f)
+}
+
+// NewKonnoOhmachiSmoother returns a smoother using the Konno & Ohmachi (1998) window with the given bandwidth
+// coefficient b (typically 20-188, larger values mean less smoothing). If outputFrequencies is nil, the smoothed
+// spectrum is evaluated at the input frequencies.
+func NewKonnoOhmachiSmoother(frequencies, outputFrequencies []float64, bandwidth float64) (*Smoother, error) {
+	if bandwidth <= 0 {
+		return nil, errors.New("bandwidth must be a positive number")
+	}
+	kernel := func(distance float64) float64 {
+		return sinc4(bandwidth * distance)
+	}
+	halfWidth := math.Pi / bandwidth
+	return newSmoother(frequencies, outputFrequencies, log10Coordinate, kernel, halfWidth, windowLobes*halfWidth)
+}
+
+// NewParzenSmoother returns a smoother using the Parzen window with the given bandwidth in Hz.
+// If outputFrequencies is nil, the smoothed spectrum is evaluated at the input frequencies.
+func NewParzenSmoother(frequencies, outputFrequencies []float64, bandwidth float64) (*Smoother, error) {
+	if bandwidth <= 0 {
+		return nil, errors.New("bandwidth must be a positive number")
+	}
+	u := 280 / (151 * bandwidth)
+	kernel := func(distance float64) float64 {
+		return sinc4(math.Pi * u * distance / 2)
+	}
+	identity := func(f float64) float64 { return f }
+	halfWidth := 2 / u
+	return newSmoother(frequencies, outputFrequencies, identity, kernel, halfWidth, windowLobes*halfWidth)
+}
+
+// NewLogMovingAverageSmoother returns a smoother averaging the spectrum over a rectangular window of halfWidth
+// decades on each side of the center frequency. If outputFrequencies is nil, the smoothed spectrum is evaluated at the
+// input frequencies.
+func NewLogMovingAverageSmoother(frequencies, outputFrequencies []float64, halfWidth float64) (*Smoother, error) {
+	if halfWidth <= 0 {
+		return nil, errors.New("half width must be a positive number")
+	}
+	kernel := func(distance float64) float64 {
+		if math.Abs(distance) <= halfWidth {
+			return 1
+		}
+		return 0
+	}
+	return newSmoother(frequencies, outputFrequencies, log10Coordinate, kernel, halfWidth, halfWidth)
+}
+
+// KonnoOhmachiSmoothing smooths the amplitudes with the Konno & Ohmachi window and evaluates them at the output
+// frequencies.
+func KonnoOhmachiSmoothing(frequencies, amplitudes, outputFrequencies []float64, bandwidth float64) ([]float64, error) {
+	if len(frequencies) != len(amplitudes) {
+		return nil, errors.New("frequencies and amplitudes must be of equal length")
+	}
+	smoother, err := NewKonnoOhmachiSmoother(frequencies, outputFrequencies, bandwidth)
+	if err != nil {
+		return nil, err
+	}
+	return smoother.Apply(amplitudes)
+}
+
+// ParzenSmoothing smooths the amplitudes with the Parzen window and evaluates them at the output frequencies.
+func ParzenSmoothing(frequencies, amplitudes, outputFrequencies []float64, bandwidth float64) ([]float64, error) {
+	if len(frequencies) != len(amplitudes) {
+		return nil, errors.New("frequencies and amplitudes must be of equal length")
+	}
+	smoother, err := NewParzenSmoother(frequencies, outputFrequencies, bandwidth)
+	if err != nil {
+		return nil, err
+	}
+	return smoother.Apply(amplitudes)
+}
+
+// LogMovingAverageSmoothing averages the amplitudes over a log-frequency window and evaluates them at the output
+// frequencies.
+func LogMovingAverageSmoothing(frequencies, amplitudes, outputFrequencies []float64, halfWidth float64) (
+	[]float64, error,
+) {
+	if len(frequencies) != len(amplitudes) {
+		return nil, errors.New("frequencies and amplitudes must be of equal length")
+	}
+	smoother, err := NewLogMovingAverageSmoother(frequencies, outputFrequencies, halfWidth)
+	if err != nil {
+		return nil, err
+	}
+	return smoother.Apply(amplitudes)
+}
+
+// LogSpacedFrequencies returns count frequencies logarithmically spaced between minFrequency and maxFrequency.
+func LogSpacedFrequencies(minFrequency, maxFrequency float64, count int) []float64 {
+	return np.LogSpace(math.Log10(minFrequency), math.Log10(maxFrequency), float64(count))
+}
@@ -0,0 +1,89 @@
+package fourier_spectrum
+
+import (
+	td "github.com/geoport/GoQuakeLib/TestData"
+	"math"
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func bruteForceKonnoOhmachi(frequencies, amplitudes []float64, fc, bandwidth float64) float64 {
+	var total, weightSum float64
+	for i, f := range frequencies {
+		if f <= 0 {
+			continue
+		}
+		w := 1.0
+		if f != fc {
+			x := bandwidth * math.Log10(f/fc)
+			w = math.Pow(math.Sin(x)/x, 4)
+		}
+		total += w * amplitudes[i]
+		weightSum += w
+	}
+	return total / weightSum
+}
+
+func TestKonnoOhmachiSmoothing(t *testing.T) {
+	acc := td.TestMotion["Accelerations"].([]float64)
+	timeStep := td.TestMotion["TimeStep"].(float64)
+	frequencies, amplitudes, _ := FourierSpectrum(acc, timeStep)
+	outputFrequencies := LogSpacedFrequencies(0.1, 9, 50)
+
+	smoothed, err := KonnoOhmachiSmoothing(frequencies, amplitudes, outputFrequencies, 40)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, i := range []int{0, 25, 49} {
+		expected := bruteForceKonnoOhmachi(frequencies, amplitudes, outputFrequencies[i], 40)
+		if math.Abs(smoothed[i]-expected)/expected > 1e-3 {
+			t.Errorf("Expected smoothed amplitude %e at %f Hz, got %e", expected, outputFrequencies[i], smoothed[i])
+		}
+	}
+
+	constant := np.Repeat(2, len(frequencies))
+	smoothed, _ = KonnoOhmachiSmoothing(frequencies, constant, nil, 40)
+	if !np.AllClose(smoothed[1:], constant[1:], 1e-10) {
+		t.Errorf("Expected smoothing of a constant spectrum to be constant")
+	}
+
+	if _, err = KonnoOhmachiSmoothing(frequencies, amplitudes[1:], nil, 40); err == nil {
+		t.Errorf("Expected error for mismatched lengths")
+	}
+}
+
+func TestParzenSmoothing(t *testing.T) {
+	frequencies := np.Arange(0, 10, 0.01)
+	amplitudes := make([]float64, len(frequencies))
+	amplitudes[500] = 1
+	smoothed, err := ParzenSmoothing(frequencies, amplitudes, nil, 0.5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if np.ArgMax(smoothed) != 500 || smoothed[500] >= 1 || smoothed[450] <= 0 {
+		t.Errorf("Expected a spread peak centered at 5 Hz, got peak at index %d", np.ArgMax(smoothed))
+	}
+	if math.Abs(smoothed[490]-smoothed[510])/smoothed[490] > 1e-3 {
+		t.Errorf("Expected symmetric smoothing, got %e and %e", smoothed[490], smoothed[510])
+	}
+}
+
+func TestLogMovingAverageSmoothing(t *testing.T) {
+	frequencies := []float64{0, 1, 2, 3, 4, 5}
+	amplitudes := []float64{9, 1, 2, 3, 4, 5}
+	smoothed, err := LogMovingAverageSmoothing(frequencies, amplitudes, []float64{2}, math.Log10(2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if smoothed[0] != 2.5 {
+		t.Errorf("Expected 2.5, got %f", smoothed[0])
+	}
+}
+
+func TestLogSpacedFrequencies(t *testing.T) {
+	frequencies := LogSpacedFrequencies(0.1, 10, 3)
+	if !np.AllClose(frequencies, []float64{0.1, 1, 10}, 1e-10) {
+		t.Errorf("Expected [0.1 1 10], got %v", frequencies)
+	}
+}
@@ -0,0 +1,62 @@
+package fourier_spectrum
+
+import (
+	"errors"
+	"math"
+)
+
+// GetWindow returns a symmetric taper window of the given length.
+//
+// Supported windows are "boxcar" (or an empty string), "hann", "hamming", "blackman" and "tukey". For the tukey
+// window taperFraction is the fraction of the window inside the cosine tapers (0 gives a boxcar, 1 gives a hann
+// window); it is ignored by the other windows.
+func GetWindow(window string, length int, taperFraction float64) ([]float64, error) {
+	if length < 1 {
+		return nil, errors.New("window length must be a positive integer")
+	}
+	weights := make([]float64, length)
+	if length == 1 {
+		weights[0] = 1
+		return weights, nil
+	}
+	m := float64(length - 1)
+
+	switch window {
+	case "", "boxcar":
+		for i := range weights {
+			weights[i] = 1
+		}
+	case "hann":
+		for i := range weights {
+			weights[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/m)
+		}
+	case "hamming":
+		for i := range weights {
+			weights[i] = 0.54 - 0.46*math.Cos(2*math.Pi*float64(i)/m)
+		}
+	case "blackman":
+		for i := range weights {
+			x := 2 * math.Pi * float64(i) / m
+			weights[i] = 0.42 - 0.5*math.Cos(x) + 0.08*math.Cos(2*x)
+		}
+	case "tukey":
+		if taperFraction < 0 || taperFraction > 1 {
+			return nil, errors.New("taper fraction must be between 0 and 1")
+		}
+		edge := taperFraction * m / 2
+		for i := range weights {
+			x := float64(i)
+			if x > m/2 {
+				x = m - x
+			}
+			if x < edge {
+				weights[i] = 0.5 - 0.5*math.Cos(math.Pi*x/edge)
+			} else {
+				weights[i] = 1
+			}
+		}
+	default:
+		return nil, errors.New("window type not supported")
+	}
+	return weights, nil
+}
@@ -0,0 +1,28 @@
+package fourier_spectrum
+
+import (
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func TestGetWindow(t *testing.T) {
+	hann, _ := GetWindow("hann", 5, 0)
+	if !np.AllClose(hann, []float64{0, 0.5, 1, 0.5, 0}, 1e-12) {
+		t.Errorf("Expected hann window [0 0.5 1 0.5 0], got %v", hann)
+	}
+
+	tukey, _ := GetWindow("tukey", 11, 0.4)
+	if tukey[0] != 0 || tukey[5] != 1 || tukey[10] != 0 || np.Round(tukey[1], 3) != 0.5 {
+		t.Errorf("Expected tukey window with tapered edges, got %v", tukey)
+	}
+
+	boxcar, _ := GetWindow("", 4, 0)
+	if !np.AllClose(boxcar, np.Ones(4), 0) {
+		t.Errorf("Expected boxcar window of ones, got %v", boxcar)
+	}
+
+	if _, err := GetWindow("unknown", 4, 0); err == nil {
+		t.Errorf("Expected error for unsupported window")
+	}
+}
@@ -2,14 +2,4 @@ module github.com/geoport/GoQuakeLib
 
 go 1.20
 
-require (
-	github.com/eripe970/go-dsp-utils v0.0.0-20221126143949-9c8142dc8c54
-	github.com/geoport/numpy4go v0.1.61
-)
-
-require (
-	github.com/goccmack/godsp v0.1.1 // indirect
-	github.com/goccmack/goutil v0.4.0 // indirect
-	github.com/mattetti/audio v0.0.0-20190404201502-c6aebeb78429 // indirect
-	github.com/mjibson/go-dsp v0.0.0-20180508042940-11479a337f12 // indirect
-)
+require github.com/geoport/numpy4go v0.1.61
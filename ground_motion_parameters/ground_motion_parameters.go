@@ -4,6 +4,7 @@ import (
 	fs "github.com/geoport/GoQuakeLib/fourier_spectrum"
 	Filtering "github.com/geoport/GoQuakeLib/processing/filtering"
 	GoQuake "github.com/geoport/GoQuakeLib/response_spectra"
+	tf "github.com/geoport/GoQuakeLib/time_frequency"
 	ts "github.com/geoport/GoQuakeLib/time_series"
 	"math"
 	"sort"
@@ -31,6 +32,7 @@ type GMPData struct {
 	BracketedDuration             float64
 	SignificantDuration           float64
 	EffectiveDuration             float64
+	EnvelopeDuration              float64
 	AriasIntensity                float64
 	AriasIntensityArray           []float64
 	RmsAcceleration               float64
@@ -184,6 +186,25 @@ func (gmp *GMPData) CalcBracketedDuration(motion ts.MotionData) {
 	gmp.BracketedDuration = motion.TimeStep + motion.Times[indexes[len(indexes)-1]] - motion.Times[indexes[0]]
 }
 
+// EnvelopeDurationFraction is the fraction of the peak of the Hilbert envelope of the accelerations whose first and
+// last exceedances bound the envelope duration, the threshold of the bracketed and uniform durations.
+const EnvelopeDurationFraction = 0.05
+
+// CalcEnvelopeDuration sets the time between the first and the last sample where the Hilbert envelope of the
+// accelerations reaches EnvelopeDurationFraction of its peak. The field is not set if the envelope cannot be
+// computed, such as for a motion without accelerations or with zero accelerations.
+func (gmp *GMPData) CalcEnvelopeDuration(motion ts.MotionData) {
+	hilbert, err := tf.Hilbert(motion, tf.HilbertOptions{})
+	if err != nil {
+		return
+	}
+	duration, _, err := hilbert.EnvelopeDuration(EnvelopeDurationFraction)
+	if err != nil {
+		return
+	}
+	gmp.EnvelopeDuration = duration
+}
+
 func (gmp *GMPData) CalcAriasIntensity(motion ts.MotionData) {
 	g := 9.81 // m/s^2
 	accelerations := motion.Accelerations
@@ -250,6 +271,8 @@ func (gmp *GMPData) CalcCumulativeAbsoluteVelocity(motion ts.MotionData) {
 	gmp.CumulativeAbsoluteVelocity = CAV
 }
 
+// CalcGMP sets all the parameters of the motion, whose velocities, displacements and times must be set, and of its
+// spectra.
 func (gmp *GMPData) CalcGMP(motion ts.MotionData, spectra *GoQuake.ResponseSpectraData) *GMPData {
 	gmp.CalcAriasIntensity(motion)
 	gmp.CalcPGA(motion)
@@ -266,6 +289,7 @@ func (gmp *GMPData) CalcGMP(motion ts.MotionData, spectra *GoQuake.ResponseSpect
 	gmp.CalcMeanPeriod(motion)
 	gmp.CalcUniformDuration(motion)
 	gmp.CalcBracketedDuration(motion)
+	gmp.CalcEnvelopeDuration(motion)
 	gmp.CalcSignificantDuration(motion)
 	gmp.CalcRMSAcceleration(motion)
 	gmp.CalcRMSVelocity(motion)
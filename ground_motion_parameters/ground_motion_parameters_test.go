@@ -167,6 +167,21 @@ func TestGMP_CalcBracketedDuration(t *testing.T) {
 	}
 }
 
+func TestGMP_CalcEnvelopeDuration(t *testing.T) {
+	expected := 31.85
+	gmp.CalcEnvelopeDuration(testMotion)
+	output := np.Round(gmp.EnvelopeDuration, 5)
+	if output != expected {
+		t.Errorf("Expected Envelope Duration = %f, got %f", expected, output)
+	}
+
+	quiet := GMPData{}
+	quiet.CalcEnvelopeDuration(ts.MotionData{Accelerations: make([]float64, 10), TimeStep: 0.01})
+	if quiet.EnvelopeDuration != 0 {
+		t.Errorf("Expected no Envelope Duration of a motion without accelerations, got %f", quiet.EnvelopeDuration)
+	}
+}
+
 func TestGMP_CalcAriasIntensity(t *testing.T) {
 	expected := 0.34798
 	gmp.CalcAriasIntensity(testMotion)
@@ -0,0 +1,301 @@
+package hvsr
+
+import (
+	"errors"
+	"math"
+
+	fs "github.com/geoport/GoQuakeLib/fourier_spectrum"
+	"github.com/geoport/GoQuakeLib/processing"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+// Options controls the windowing, transient rejection, smoothing and combination of the HVSR analysis.
+type Options struct {
+	WindowLength  float64   // length of each window in seconds
+	Overlap       float64   // overlap of consecutive windows as a fraction of the window length
+	TaperFraction float64   // fraction of each window inside the tukey taper
+	StaLength     float64   // STA window length in seconds
+	LtaLength     float64   // LTA window length in seconds
+	MinStaLta     float64   // windows with a STA/LTA ratio below this value are rejected
+	MaxStaLta     float64   // windows with a STA/LTA ratio above this value are rejected
+	Bandwidth     float64   // Konno-Ohmachi bandwidth coefficient
+	Frequencies   []float64 // frequencies of the H/V curve
+	Combination   string    // "geometric_mean", "quadratic_mean" or "rotated"
+	Azimuth       float64   // azimuth in degrees clockwise from north used by the "rotated" combination
+	MinFrequency  float64   // lower bound of the f0 peak search
+	MaxFrequency  float64   // upper bound of the f0 peak search
+}
+
+// Result holds the H/V curve statistics, the f0/A0 peak and the SESAME (2004) criteria.
+type Result struct {
+	Frequencies  []float64
+	Mean         []float64   // geometric mean of the window H/V curves
+	Lower        []float64   // Mean / StdFactors
+	Upper        []float64   // Mean * StdFactors
+	StdFactors   []float64   // multiplicative (lognormal) standard deviation of the window curves
+	WindowCurves [][]float64 // H/V curve of each accepted window
+	WindowStarts []float64   // start time (s) of each accepted window
+	NumRejected  int         // number of windows rejected by the STA/LTA check
+	F0           float64     // frequency of the H/V peak
+	A0           float64     // amplitude of the H/V peak
+	F0Std        float64     // standard deviation of the peak frequencies of the windows
+	Reliability  Criteria
+	Clarity      Criteria
+}
+
+// DefaultOptions returns the options recommended by the SESAME (2004) guidelines for ambient vibration records.
+func DefaultOptions() Options {
+	return Options{
+		WindowLength:  30,
+		Overlap:       0,
+		TaperFraction: 0.05,
+		StaLength:     1,
+		LtaLength:     30,
+		MinStaLta:     0.2,
+		MaxStaLta:     2.5,
+		Bandwidth:     40,
+		Frequencies:   fs.LogSpacedFrequencies(0.2, 20, 200),
+		Combination:   "geometric_mean",
+		MinFrequency:  0.2,
+		MaxFrequency:  20,
+	}
+}
+
+func checkInput(north, east, vertical ts.MotionData, options Options) error {
+	length := len(vertical.Accelerations)
+	if length == 0 {
+		return errors.New("no acceleration data")
+	}
+	if len(north.Accelerations) != length || len(east.Accelerations) != length {
+		return errors.New("components must be of equal length")
+	}
+	if vertical.TimeStep <= 0 {
+		return errors.New("time step must be a positive number")
+	}
+	if north.TimeStep != vertical.TimeStep || east.TimeStep != vertical.TimeStep {
+		return errors.New("components must have the same time step")
+	}
+	if options.WindowLength <= 0 || float64(length)*vertical.TimeStep < options.WindowLength {
+		return errors.New("window length must be positive and shorter than the record")
+	}
+	if options.Overlap < 0 || options.Overlap >= 1 {
+		return errors.New("overlap must be between 0 and 1")
+	}
+	if !np.Contains([]string{"geometric_mean", "quadratic_mean", "rotated"}, options.Combination) {
+		return errors.New("combination method not supported")
+	}
+	if len(options.Frequencies) == 0 {
+		return errors.New("frequencies are empty")
+	}
+	return nil
+}
+
+// rejectedSamples marks the samples where the STA/LTA ratio of any component is outside the allowed range. The LTA
+// of the samples before the first full LTA window is that of the first window.
+func rejectedSamples(components [][]float64, timeStep float64, options Options) ([]bool, error) {
+	rejected := make([]bool, len(components[0]))
+	if options.StaLength <= 0 || options.LtaLength <= 0 {
+		return rejected, nil
+	}
+	staLength := int(math.Round(options.StaLength / timeStep))
+	ltaLength := int(math.Round(options.LtaLength / timeStep))
+	if ltaLength > len(rejected) {
+		ltaLength = len(rejected)
+	}
+	for _, component := range components {
+		err, ratio := processing.StaLta(component, staLength, ltaLength)
+		if err != nil {
+			return nil, err
+		}
+		seedRatios(ratio, component, staLength, ltaLength)
+		for i := staLength - 1; i < len(ratio); i++ {
+			if ratio[i] < options.MinStaLta || ratio[i] > options.MaxStaLta {
+				rejected[i] = true
+			}
+		}
+	}
+	return rejected, nil
+}
+
+// seedRatios fills the STA/LTA ratios before the first full LTA window with the LTA of the first window.
+func seedRatios(ratio, signal []float64, staLength, ltaLength int) {
+	var lta float64
+	for _, value := range signal[:ltaLength] {
+		lta += math.Abs(value)
+	}
+	lta /= float64(ltaLength)
+	if lta == 0 {
+		return
+	}
+	var sta float64
+	for i := 0; i < ltaLength-1; i++ {
+		sta += math.Abs(signal[i])
+		if i >= staLength {
+			sta -= math.Abs(signal[i-staLength])
+		}
+		if i >= staLength-1 {
+			ratio[i] = sta / float64(staLength) / lta
+		}
+	}
+}
+
+func combineHorizontals(northSpectrum, eastSpectrum []float64, combination string) []float64 {
+	horizontal := make([]float64, len(northSpectrum))
+	for i := range horizontal {
+		if combination == "quadratic_mean" {
+			horizontal[i] = math.Sqrt((northSpectrum[i]*northSpectrum[i] + eastSpectrum[i]*eastSpectrum[i]) / 2)
+		} else {
+			horizontal[i] = math.Sqrt(northSpectrum[i] * eastSpectrum[i])
+		}
+	}
+	return horizontal
+}
+
+// CalcHVSR computes the horizontal-to-vertical spectral ratio of a three-component record.
+func CalcHVSR(north, east, vertical ts.MotionData, options Options) (*Result, error) {
+	if err := checkInput(north, east, vertical, options); err != nil {
+		return nil, err
+	}
+	timeStep := vertical.TimeStep
+	components := [][]float64{north.Accelerations, east.Accelerations, vertical.Accelerations}
+	if options.Combination == "rotated" {
+		azimuth := options.Azimuth * math.Pi / 180
+		rotated := make([]float64, len(north.Accelerations))
+		for i := range rotated {
+			rotated[i] = north.Accelerations[i]*math.Cos(azimuth) + east.Accelerations[i]*math.Sin(azimuth)
+		}
+		components = [][]float64{rotated, vertical.Accelerations}
+	}
+
+	rejected, err := rejectedSamples(components, timeStep, options)
+	if err != nil {
+		return nil, err
+	}
+
+	windowSamples := int(math.Round(options.WindowLength / timeStep))
+	step := windowSamples - int(math.Round(options.Overlap*float64(windowSamples)))
+	spectrumOptions := fs.SpectrumOptions{Window: "tukey", TaperFraction: options.TaperFraction}
+	var smoother *fs.Smoother
+
+	result := Result{Frequencies: options.Frequencies}
+	for start := 0; start+windowSamples <= len(rejected); start += step {
+		if anyTrue(rejected[start : start+windowSamples]) {
+			result.NumRejected++
+			continue
+		}
+
+		spectra := make([][]float64, len(components))
+		for c, component := range components {
+			spectrum, err := fs.ComplexFourierSpectrum(component[start:start+windowSamples], timeStep, spectrumOptions)
+			if err != nil {
+				return nil, err
+			}
+			if smoother == nil {
+				smoother, err = fs.NewKonnoOhmachiSmoother(spectrum.Frequencies, options.Frequencies, options.Bandwidth)
+				if err != nil {
+					return nil, err
+				}
+			}
+			spectra[c], err = smoother.Apply(spectrum.Amplitudes)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var horizontal, verticalSpectrum []float64
+		if options.Combination == "rotated" {
+			horizontal, verticalSpectrum = spectra[0], spectra[1]
+		} else {
+			horizontal = combineHorizontals(spectra[0], spectra[1], options.Combination)
+			verticalSpectrum = spectra[2]
+		}
+		result.WindowCurves = append(result.WindowCurves, np.DividedBy(horizontal, verticalSpectrum))
+		result.WindowStarts = append(result.WindowStarts, float64(start)*timeStep)
+	}
+	if len(result.WindowCurves) == 0 {
+		return nil, errors.New("all windows are rejected")
+	}
+
+	result.calcStatistics()
+	if err = result.pickPeak(options.MinFrequency, options.MaxFrequency); err != nil {
+		return nil, err
+	}
+	result.Reliability = checkReliability(&result, options.WindowLength)
+	result.Clarity = checkClarity(&result, options.MinFrequency, options.MaxFrequency)
+
+	return &result, nil
+}
+
+func anyTrue(values []bool) bool {
+	for _, value := range values {
+		if value {
+			return true
+		}
+	}
+	return false
+}
+
+// calcStatistics computes the lognormal mean and standard deviation of the window curves.
+func (r *Result) calcStatistics() {
+	numFrequencies := len(r.Frequencies)
+	numWindows := float64(len(r.WindowCurves))
+	r.Mean = make([]float64, numFrequencies)
+	r.StdFactors = make([]float64, numFrequencies)
+	r.Lower = make([]float64, numFrequencies)
+	r.Upper = make([]float64, numFrequencies)
+	for i := 0; i < numFrequencies; i++ {
+		var sum, sumSquares float64
+		for _, curve := range r.WindowCurves {
+			logValue := math.Log(curve[i])
+			sum += logValue
+			sumSquares += logValue * logValue
+		}
+		mean := sum / numWindows
+		variance := 0.
+		if numWindows > 1 {
+			variance = math.Max((sumSquares-numWindows*mean*mean)/(numWindows-1), 0)
+		}
+		r.Mean[i] = math.Exp(mean)
+		r.StdFactors[i] = math.Exp(math.Sqrt(variance))
+		r.Lower[i] = r.Mean[i] / r.StdFactors[i]
+		r.Upper[i] = r.Mean[i] * r.StdFactors[i]
+	}
+}
+
+func peakIndex(curve, frequencies []float64, minFrequency, maxFrequency float64) int {
+	index := -1
+	for i, f := range frequencies {
+		if f < minFrequency || f > maxFrequency {
+			continue
+		}
+		if index < 0 || curve[i] > curve[index] {
+			index = i
+		}
+	}
+	return index
+}
+
+// pickPeak finds f0 and A0 on the mean curve and the scatter of the window peak frequencies.
+func (r *Result) pickPeak(minFrequency, maxFrequency float64) error {
+	index := peakIndex(r.Mean, r.Frequencies, minFrequency, maxFrequency)
+	if index < 0 {
+		return errors.New("no frequencies in the peak search range")
+	}
+	r.F0 = r.Frequencies[index]
+	r.A0 = r.Mean[index]
+
+	peakFrequencies := make([]float64, len(r.WindowCurves))
+	for w, curve := range r.WindowCurves {
+		peakFrequencies[w] = r.Frequencies[peakIndex(curve, r.Frequencies, minFrequency, maxFrequency)]
+	}
+	if len(peakFrequencies) > 1 {
+		mean := np.Mean(peakFrequencies)
+		var sumSquares float64
+		for _, f := range peakFrequencies {
+			sumSquares += (f - mean) * (f - mean)
+		}
+		r.F0Std = math.Sqrt(sumSquares / float64(len(peakFrequencies)-1))
+	}
+	return nil
+}
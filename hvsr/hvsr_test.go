@@ -0,0 +1,124 @@
+package hvsr
+
+import (
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	"math"
+	"math/rand"
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+const testTimeStep = 0.01
+
+func whiteNoise(length int, random *rand.Rand) []float64 {
+	noise := make([]float64, length)
+	for i := range noise {
+		noise[i] = random.NormFloat64()
+	}
+	return noise
+}
+
+// resonate adds the output of a two-pole band-pass resonator peaking at the given frequency to the signal.
+func resonate(signal []float64, frequency float64) []float64 {
+	r := 0.97
+	a1 := 2 * r * math.Cos(2*math.Pi*frequency*testTimeStep)
+	a2 := -r * r
+	resonance := make([]float64, len(signal))
+	output := make([]float64, len(signal))
+	for i := range signal {
+		resonance[i] = (1 - r) * signal[i]
+		if i > 1 {
+			resonance[i] += -(1-r)*signal[i-2] + a1*resonance[i-1] + a2*resonance[i-2]
+		}
+		output[i] = signal[i] + 4*resonance[i]
+	}
+	return output
+}
+
+// synthetic ambient vibration record with a 2 Hz site resonance on the horizontal components
+func syntheticRecord() (ts.MotionData, ts.MotionData, ts.MotionData) {
+	random := rand.New(rand.NewSource(7))
+	length := 60000
+	north := ts.MotionData{Accelerations: resonate(whiteNoise(length, random), 2), TimeStep: testTimeStep}
+	east := ts.MotionData{Accelerations: resonate(whiteNoise(length, random), 2), TimeStep: testTimeStep}
+	vertical := ts.MotionData{Accelerations: whiteNoise(length, random), TimeStep: testTimeStep}
+	return north, east, vertical
+}
+
+func TestCalcHVSR(t *testing.T) {
+	north, east, vertical := syntheticRecord()
+	result, err := CalcHVSR(north, east, vertical, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.WindowCurves) != 20 || result.NumRejected != 0 {
+		t.Errorf("Expected 20 accepted windows, got %d (%d rejected)", len(result.WindowCurves), result.NumRejected)
+	}
+	if math.Abs(result.F0-2) > 0.2 {
+		t.Errorf("Expected f0 close to 2 Hz, got %f", result.F0)
+	}
+	if result.A0 < 2 {
+		t.Errorf("Expected A0 above 2, got %f", result.A0)
+	}
+	if !result.Reliability.Passed || !result.Clarity.Passed {
+		t.Errorf("Expected SESAME criteria to pass, got %v and %v", result.Reliability, result.Clarity)
+	}
+	for i := range result.Mean {
+		if result.Lower[i] > result.Mean[i] || result.Upper[i] < result.Mean[i] {
+			t.Errorf("Expected mean curve between its bounds at %f Hz", result.Frequencies[i])
+			break
+		}
+	}
+
+	options := DefaultOptions()
+	options.Combination = "rotated"
+	rotated, _ := CalcHVSR(north, east, vertical, options)
+	if math.Abs(rotated.F0-2) > 0.2 {
+		t.Errorf("Expected f0 close to 2 Hz for the rotated combination, got %f", rotated.F0)
+	}
+}
+
+func TestCalcHVSR_TransientRejection(t *testing.T) {
+	north, east, vertical := syntheticRecord()
+	vertical.Accelerations = append([]float64{}, vertical.Accelerations...)
+	for i := 45000; i < 45100; i++ {
+		vertical.Accelerations[i] *= 50
+	}
+	result, err := CalcHVSR(north, east, vertical, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.NumRejected != 1 || np.Contains(result.WindowStarts, 450) {
+		t.Errorf("Expected the window containing the transient to be rejected, got %v", result.WindowStarts)
+	}
+}
+
+func TestCalcHVSR_LeadingTransientRejection(t *testing.T) {
+	north, east, vertical := syntheticRecord()
+	// a transient inside the first window, before the first full LTA window
+	vertical.Accelerations = append([]float64{}, vertical.Accelerations...)
+	for i := 1000; i < 1100; i++ {
+		vertical.Accelerations[i] *= 50
+	}
+	result, err := CalcHVSR(north, east, vertical, DefaultOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.NumRejected != 1 || np.Contains(result.WindowStarts, 0) {
+		t.Errorf("Expected the first window to be rejected, got %v", result.WindowStarts)
+	}
+}
+
+func TestCalcHVSR_InvalidInput(t *testing.T) {
+	north, east, vertical := syntheticRecord()
+	east.TimeStep = 0.02
+	if _, err := CalcHVSR(north, east, vertical, DefaultOptions()); err == nil {
+		t.Errorf("Expected error for different time steps")
+	}
+	options := DefaultOptions()
+	options.Combination = "max"
+	if _, err := CalcHVSR(north, north, vertical, options); err == nil {
+		t.Errorf("Expected error for unsupported combination")
+	}
+}
@@ -0,0 +1,99 @@
+package hvsr
+
+import (
+	"math"
+)
+
+// Criteria holds the outcome of each SESAME (2004) check and whether the set of checks is satisfied.
+type Criteria struct {
+	Checks []bool
+	Passed bool
+}
+
+// sesameThresholds returns the frequency (epsilon) and amplitude (theta) stability thresholds of SESAME (2004)
+// Table 3 for the given peak frequency.
+func sesameThresholds(f0 float64) (float64, float64) {
+	switch {
+	case f0 < 0.2:
+		return 0.25 * f0, 3.0
+	case f0 < 0.5:
+		return 0.20 * f0, 2.5
+	case f0 < 1.0:
+		return 0.15 * f0, 2.0
+	case f0 < 2.0:
+		return 0.10 * f0, 1.78
+	default:
+		return 0.05 * f0, 1.58
+	}
+}
+
+// checkReliability evaluates the three SESAME criteria for a reliable H/V curve, all of which must be satisfied.
+func checkReliability(r *Result, windowLength float64) Criteria {
+	numWindows := float64(len(r.WindowCurves))
+	stdLimit := 2.
+	if r.F0 < 0.5 {
+		stdLimit = 3
+	}
+	stdSatisfied := true
+	for i, f := range r.Frequencies {
+		if f > 0.5*r.F0 && f < 2*r.F0 && r.StdFactors[i] >= stdLimit {
+			stdSatisfied = false
+		}
+	}
+
+	checks := []bool{
+		r.F0 > 10/windowLength,
+		windowLength*numWindows*r.F0 > 200,
+		stdSatisfied,
+	}
+	return Criteria{Checks: checks, Passed: countTrue(checks) == len(checks)}
+}
+
+// checkClarity evaluates the six SESAME criteria for a clear H/V peak, at least five of which must be satisfied. The
+// peaks of the mean +/- one standard deviation curves are searched in the frequency range of the f0 search.
+func checkClarity(r *Result, minFrequency, maxFrequency float64) Criteria {
+	epsilon, theta := sesameThresholds(r.F0)
+
+	var lowerTrough, upperTrough bool
+	for i, f := range r.Frequencies {
+		if r.Mean[i] >= r.A0/2 {
+			continue
+		}
+		if f >= r.F0/4 && f < r.F0 {
+			lowerTrough = true
+		}
+		if f > r.F0 && f <= 4*r.F0 {
+			upperTrough = true
+		}
+	}
+
+	f0Index := 0
+	for i, f := range r.Frequencies {
+		if f == r.F0 {
+			f0Index = i
+		}
+	}
+	upperPeak := r.Frequencies[peakIndex(r.Upper, r.Frequencies, minFrequency, maxFrequency)]
+	lowerPeak := r.Frequencies[peakIndex(r.Lower, r.Frequencies, minFrequency, maxFrequency)]
+	stablePeak := math.Abs(upperPeak-r.F0) <= 0.05*r.F0 && math.Abs(lowerPeak-r.F0) <= 0.05*r.F0
+
+	checks := []bool{
+		lowerTrough,
+		upperTrough,
+		r.A0 > 2,
+		stablePeak,
+		r.F0Std < epsilon,
+		r.StdFactors[f0Index] < theta,
+	}
+	return Criteria{Checks: checks, Passed: countTrue(checks) >= 5}
+}
+
+func countTrue(values []bool) int {
+	count := 0
+	for _, value := range values {
+		if value {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,67 @@
+package hvsr
+
+import (
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func TestSesameThresholds(t *testing.T) {
+	epsilon, theta := sesameThresholds(1.5)
+	if np.Round(epsilon, 5) != 0.15 || theta != 1.78 {
+		t.Errorf("Expected 0.15 and 1.78, got %f and %f", epsilon, theta)
+	}
+}
+
+func TestCheckReliability(t *testing.T) {
+	result := Result{
+		Frequencies:  []float64{0.5, 1, 2, 4},
+		StdFactors:   []float64{1.5, 1.5, 1.5, 1.5},
+		WindowCurves: make([][]float64, 10),
+		F0:           2,
+	}
+	criteria := checkReliability(&result, 20)
+	if !criteria.Passed {
+		t.Errorf("Expected reliability criteria to pass, got %v", criteria.Checks)
+	}
+	result.StdFactors[2] = 2.5
+	criteria = checkReliability(&result, 20)
+	if criteria.Passed || criteria.Checks[2] {
+		t.Errorf("Expected amplitude scatter criterion to fail, got %v", criteria.Checks)
+	}
+}
+
+func TestCheckClarity(t *testing.T) {
+	result := Result{
+		Frequencies: []float64{0.5, 1, 2, 4, 8},
+		Mean:        []float64{1, 1.2, 4, 1.2, 1},
+		StdFactors:  []float64{1.2, 1.2, 1.2, 1.2, 1.2},
+		F0:          2,
+		A0:          4,
+		F0Std:       0.05,
+	}
+	result.Lower = make([]float64, 5)
+	result.Upper = make([]float64, 5)
+	for i := range result.Mean {
+		result.Lower[i] = result.Mean[i] / result.StdFactors[i]
+		result.Upper[i] = result.Mean[i] * result.StdFactors[i]
+	}
+	criteria := checkClarity(&result, 0.5, 8)
+	if !criteria.Passed || countTrue(criteria.Checks) != 6 {
+		t.Errorf("Expected all clarity criteria to pass, got %v", criteria.Checks)
+	}
+	// the peaks of the standard deviation curves outside the f0 search range are ignored
+	result.Upper[4], result.Lower[4] = 10, 10
+	if criteria = checkClarity(&result, 0.5, 4); !criteria.Checks[3] {
+		t.Errorf("Expected a stable peak in the search range, got %v", criteria.Checks)
+	}
+	if criteria = checkClarity(&result, 0.5, 8); criteria.Checks[3] {
+		t.Errorf("Expected an unstable peak in the full range, got %v", criteria.Checks)
+	}
+	result.A0 = 1.9
+	result.F0Std = 0.5
+	criteria = checkClarity(&result, 0.5, 8)
+	if criteria.Passed {
+		t.Errorf("Expected clarity criteria to fail, got %v", criteria.Checks)
+	}
+}
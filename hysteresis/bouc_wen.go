@@ -0,0 +1,82 @@
+package hysteresis
+
+import (
+	"errors"
+	"math"
+)
+
+// BoucWen is the smooth hysteretic material of Bouc (1967) and Wen (1976), f = a k0 u + (1 - a) Fy z, whose
+// hysteretic variable evolves as dz/du = (1 - |z|^n (beta sgn(du z) + gamma)) / uy.
+type BoucWen struct {
+	stiffness, yieldForce, hardening float64
+	beta, gamma, exponent            float64
+	committed, trial                 boucWenState
+}
+
+type boucWenState struct {
+	u, z, f, k float64
+}
+
+// NewBoucWen returns the Bouc-Wen material with the initial stiffness (kN/m), yield force (kN), post-yield to initial
+// stiffness ratio and shape parameters. beta + gamma = 1 gives the yield force as the strength of the hysteretic
+// component, beta = gamma = 0.5 is common, and large exponents n approach the bilinear material.
+func NewBoucWen(stiffness, yieldForce, hardeningRatio, beta, gamma, n float64) (*BoucWen, error) {
+	if err := checkBackbone(stiffness, yieldForce, hardeningRatio); err != nil {
+		return nil, err
+	}
+	if beta <= 0 || math.Abs(gamma) > beta {
+		return nil, errors.New("beta must be positive and not smaller than the magnitude of gamma")
+	}
+	if n < 1 {
+		return nil, errors.New("exponent must be at least 1")
+	}
+	m := BoucWen{
+		stiffness: stiffness, yieldForce: yieldForce, hardening: hardeningRatio, beta: beta, gamma: gamma, exponent: n,
+	}
+	m.committed.k = stiffness
+	m.trial = m.committed
+	return &m, nil
+}
+
+// slope returns dz/du times the yield displacement.
+func (m *BoucWen) slope(z, du float64) float64 {
+	return 1 - math.Pow(math.Abs(z), m.exponent)*(m.beta*sign(du*z)+m.gamma)
+}
+
+func (m *BoucWen) SetTrialDisplacement(u float64) (float64, float64) {
+	c := m.committed
+	du := u - c.u
+	uy := m.yieldForce / m.stiffness
+	// the hysteretic variable is integrated with the fourth order Runge-Kutta method in steps of at most uy/50
+	steps := int(math.Ceil(math.Abs(du) / (0.02 * uy)))
+	h := du / float64(steps) / uy
+	z := c.z
+	for i := 0; i < steps; i++ {
+		k1 := m.slope(z, du)
+		k2 := m.slope(z+h*k1/2, du)
+		k3 := m.slope(z+h*k2/2, du)
+		k4 := m.slope(z+h*k3, du)
+		z += h * (k1 + 2*k2 + 2*k3 + k4) / 6
+	}
+	direction := du
+	if direction == 0 {
+		direction = 1
+	}
+	f := m.hardening*m.stiffness*u + (1-m.hardening)*m.yieldForce*z
+	k := m.hardening*m.stiffness + (1-m.hardening)*m.stiffness*m.slope(z, direction)
+	m.trial = boucWenState{u: u, z: z, f: f, k: k}
+	return f, k
+}
+
+func (m *BoucWen) CommitState() {
+	m.committed = m.trial
+}
+
+func (m *BoucWen) InitialStiffness() float64 {
+	return m.stiffness
+}
+
+func (m *BoucWen) Clone() Material {
+	clone, _ := NewBoucWen(m.stiffness, m.yieldForce, m.hardening, m.beta, m.gamma, m.exponent)
+	return clone
+}
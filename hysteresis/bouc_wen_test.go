@@ -0,0 +1,35 @@
+package hysteresis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoucWen(t *testing.T) {
+	material, err := NewBoucWen(1000, 10, 0.1, 0.5, 0.5, 20)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	bilinear, _ := NewBilinear(1000, 10, 0.1)
+	displacements := path(1e-4, 0, 0.03, -0.03, 0.03)
+	smooth := load(material, displacements)
+	sharp := load(bilinear, displacements)
+	for i := range displacements {
+		if math.Abs(smooth[i]-sharp[i]) > 0.05*10 {
+			t.Fatalf("Expected the bilinear force %f at %f with a large exponent, got %f",
+				sharp[i], displacements[i], smooth[i])
+		}
+	}
+
+	// the tangent stiffness matches the force increments
+	material, _ = NewBoucWen(1000, 10, 0.05, 0.5, 0.5, 1)
+	load(material, path(1e-4, 0, 0.012))
+	f0, k := material.SetTrialDisplacement(0.012)
+	f1, _ := material.SetTrialDisplacement(0.012 + 1e-6)
+	if math.Abs((f1-f0)/1e-6-k) > 1e-3*k {
+		t.Errorf("Expected tangent stiffness %f, got %f", (f1-f0)/1e-6, k)
+	}
+	if _, err := NewBoucWen(1000, 10, 0, 0.5, 0.6, 1); err == nil {
+		t.Errorf("Expected error for gamma larger than beta")
+	}
+}
@@ -0,0 +1,68 @@
+package hysteresis
+
+import (
+	"errors"
+
+	"github.com/geoport/GoQuakeLib/internal/newmark"
+)
+
+// Material is the force-deformation relation of the spring of an oscillator, in kN and m. SetTrialDisplacement
+// returns the restoring force and tangent stiffness at a displacement reached from the last committed state, so it
+// can be called repeatedly during the iterations of a time step; CommitState accepts the last trial state.
+type Material interface {
+	SetTrialDisplacement(u float64) (float64, float64)
+	CommitState()
+	InitialStiffness() float64
+	// Clone returns a copy of the material in its initial, unloaded state.
+	Clone() Material
+}
+
+// Bilinear is an elastoplastic material with kinematic hardening. A zero hardening ratio is elastic-perfectly-plastic.
+type Bilinear struct {
+	spring *newmark.Bilinear
+}
+
+func checkBackbone(stiffness, yieldForce, hardeningRatio float64) error {
+	if stiffness <= 0 || yieldForce <= 0 {
+		return errors.New("stiffness and yield force must be positive")
+	}
+	if hardeningRatio < 0 || hardeningRatio >= 1 {
+		return errors.New("hardening ratio must be in [0, 1)")
+	}
+	return nil
+}
+
+// NewBilinear returns the bilinear material with the initial stiffness (kN/m), yield force (kN) and post-yield to
+// initial stiffness ratio.
+func NewBilinear(stiffness, yieldForce, hardeningRatio float64) (*Bilinear, error) {
+	if err := checkBackbone(stiffness, yieldForce, hardeningRatio); err != nil {
+		return nil, err
+	}
+	return &Bilinear{spring: newmark.NewBilinear(stiffness, yieldForce, hardeningRatio)}, nil
+}
+
+func (m *Bilinear) SetTrialDisplacement(u float64) (float64, float64) {
+	return m.spring.SetTrialDisplacement(u)
+}
+
+func (m *Bilinear) CommitState() {
+	m.spring.CommitState()
+}
+
+func (m *Bilinear) InitialStiffness() float64 {
+	stiffness, _, _ := m.spring.Parameters()
+	return stiffness
+}
+
+func (m *Bilinear) Clone() Material {
+	clone, _ := NewBilinear(m.spring.Parameters())
+	return clone
+}
+
+// sign returns -1 for negative values and 1 otherwise.
+func sign(x float64) float64 {
+	if x < 0 {
+		return -1
+	}
+	return 1
+}
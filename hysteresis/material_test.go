@@ -0,0 +1,63 @@
+package hysteresis
+
+import (
+	"math"
+	"testing"
+)
+
+// load applies the displacements in turn, committing each, and returns the forces.
+func load(material Material, displacements []float64) []float64 {
+	forces := make([]float64, len(displacements))
+	for i, u := range displacements {
+		forces[i], _ = material.SetTrialDisplacement(u)
+		material.CommitState()
+	}
+	return forces
+}
+
+// path returns the displacements going linearly through the points in steps of at most h.
+func path(h float64, points ...float64) []float64 {
+	var displacements []float64
+	for i := 1; i < len(points); i++ {
+		steps := int(math.Ceil(math.Abs(points[i]-points[i-1]) / h))
+		for j := 1; j <= steps; j++ {
+			displacements = append(displacements, points[i-1]+(points[i]-points[i-1])*float64(j)/float64(steps))
+		}
+	}
+	return displacements
+}
+
+func TestBilinear(t *testing.T) {
+	material, err := NewBilinear(1000, 10, 0.1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	cases := []struct{ u, f, k float64 }{
+		{0.005, 5, 1000},
+		{0.02, 11, 100},   // hardening from the yield point at 0.01 m
+		{0.01, 1, 1000},   // elastic unloading
+		{-0.01, -10, 100}, // kinematic hardening: the compressive yield is shifted by the hardening
+	}
+	for _, c := range cases {
+		f, k := material.SetTrialDisplacement(c.u)
+		material.CommitState()
+		if math.Abs(f-c.f) > 1e-9 || math.Abs(k-c.k) > 1e-9 {
+			t.Errorf("Expected force %f and stiffness %f at %f, got %f and %f", c.f, c.k, c.u, f, k)
+		}
+	}
+
+	clone := material.Clone()
+	if f, _ := clone.SetTrialDisplacement(0.005); f != 5 {
+		t.Errorf("Expected the clone to be unloaded, got force %f", f)
+	}
+	// trial states are not kept until committed
+	material.SetTrialDisplacement(1)
+	if f, _ := material.SetTrialDisplacement(-0.01); math.Abs(f+10) > 1e-9 {
+		t.Errorf("Expected the trial to start from the committed state, got force %f", f)
+	}
+	for _, parameters := range [][3]float64{{0, 1, 0}, {1, -1, 0}, {1, 1, 1}} {
+		if _, err := NewBilinear(parameters[0], parameters[1], parameters[2]); err == nil {
+			t.Errorf("Expected error for parameters %v", parameters)
+		}
+	}
+}
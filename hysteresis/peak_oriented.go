@@ -0,0 +1,335 @@
+package hysteresis
+
+import (
+	"errors"
+	"math"
+)
+
+// PeakOrientedParameters define a peak-oriented hysteresis on a trilinear backbone with a residual plateau. Loads
+// unload with the unloading stiffness and, after crossing zero force, reload toward the largest previous excursion
+// in the loading direction, through a pinching point if the pinching factors are below 1.
+type PeakOrientedParameters struct {
+	Stiffness      float64 // initial stiffness (kN/m)
+	YieldForce     float64 // kN
+	HardeningRatio float64 // post-yield to initial stiffness ratio
+	// CappingDisplacement is the displacement (m) at which the strength starts to decrease, infinite if it does not.
+	CappingDisplacement float64
+	PostCappingRatio    float64 // negative post-capping to initial stiffness ratio
+	ResidualRatio       float64 // residual to yield strength ratio
+	// UltimateDisplacement is the displacement (m) at which the strength drops to zero, infinite if it does not.
+	UltimateDisplacement float64
+	// UnloadingExponent degrades the unloading stiffness to k0 (uy/umax)^exponent, 0.4 in the modified Takeda model.
+	UnloadingExponent    float64
+	PinchingForce        float64 // force of the pinching point relative to the targeted peak, 1 without pinching
+	PinchingDisplacement float64 // position of the pinching point between zero force and the targeted peak
+	// DeteriorationEnergy is the ratio of the hysteretic energy capacity to Fy*uy of the cyclic deterioration of
+	// Ibarra, Medina and Krawinkler (2005), 0 without cyclic deterioration.
+	DeteriorationEnergy   float64
+	DeteriorationExponent float64
+}
+
+// PeakOriented is a peak-oriented material. The Clough, modified Takeda, pinching and Ibarra-Medina-Krawinkler
+// models are configurations of it.
+type PeakOriented struct {
+	parameters       PeakOrientedParameters
+	capIntercept     float64 // force intercept of the initial post-capping branch
+	committed, trial peakOrientedState
+}
+
+type point struct {
+	u, f float64
+}
+
+// peakOrientedState holds the state of a peak-oriented material. The quantities of each loading direction are
+// indexed 0 for positive and 1 for negative.
+type peakOrientedState struct {
+	u, f, k         float64
+	zeroForce       float64  // displacement at the last crossing of zero force
+	crossed         bool     // whether the trial crossed zero force
+	peaks           [2]point // largest excursions
+	yieldForces     [2]float64
+	capIntercepts   [2]float64
+	unloadingFactor float64
+	excursionEnergy float64 // hysteretic energy of the current excursion
+	energy          float64 // total hysteretic energy
+	exhausted       bool    // whether the hysteretic energy capacity is exhausted
+}
+
+func (p PeakOrientedParameters) check() error {
+	if err := checkBackbone(p.Stiffness, p.YieldForce, p.HardeningRatio); err != nil {
+		return err
+	}
+	yieldDisplacement := p.YieldForce / p.Stiffness
+	if !math.IsInf(p.CappingDisplacement, 1) {
+		if p.CappingDisplacement <= yieldDisplacement || p.PostCappingRatio >= 0 {
+			return errors.New("capping displacement must exceed the yield displacement with a negative post-capping ratio")
+		}
+	}
+	if p.ResidualRatio < 0 || p.ResidualRatio > 1 {
+		return errors.New("residual ratio must be in [0, 1]")
+	}
+	if p.UltimateDisplacement <= yieldDisplacement {
+		return errors.New("ultimate displacement must exceed the yield displacement")
+	}
+	if p.UnloadingExponent < 0 {
+		return errors.New("unloading exponent must not be negative")
+	}
+	if p.PinchingForce <= 0 || p.PinchingForce > 1 || p.PinchingDisplacement <= 0 || p.PinchingDisplacement > 1 {
+		return errors.New("pinching factors must be in (0, 1]")
+	}
+	if p.DeteriorationEnergy < 0 || (p.DeteriorationEnergy > 0 && p.DeteriorationExponent <= 0) {
+		return errors.New("deterioration energy must not be negative, with a positive exponent")
+	}
+	return nil
+}
+
+// NewPeakOriented returns the peak-oriented material with the parameters.
+func NewPeakOriented(parameters PeakOrientedParameters) (*PeakOriented, error) {
+	if err := parameters.check(); err != nil {
+		return nil, err
+	}
+	k0, fy := parameters.Stiffness, parameters.YieldForce
+	uy := fy / k0
+	m := PeakOriented{parameters: parameters, capIntercept: math.Inf(1)}
+	if uc := parameters.CappingDisplacement; !math.IsInf(uc, 1) {
+		m.capIntercept = fy + parameters.HardeningRatio*k0*(uc-uy) - parameters.PostCappingRatio*k0*uc
+	}
+	m.committed = peakOrientedState{
+		k:               k0,
+		peaks:           [2]point{{uy, fy}, {-uy, -fy}},
+		yieldForces:     [2]float64{fy, fy},
+		capIntercepts:   [2]float64{m.capIntercept, m.capIntercept},
+		unloadingFactor: 1,
+	}
+	m.trial = m.committed
+	return &m, nil
+}
+
+// bilinearParameters returns the parameters of a peak-oriented material on a bilinear backbone.
+func bilinearParameters(stiffness, yieldForce, hardeningRatio float64) PeakOrientedParameters {
+	return PeakOrientedParameters{
+		Stiffness: stiffness, YieldForce: yieldForce, HardeningRatio: hardeningRatio,
+		CappingDisplacement: math.Inf(1), UltimateDisplacement: math.Inf(1), PinchingForce: 1, PinchingDisplacement: 1,
+	}
+}
+
+// NewClough returns the stiffness degrading model of Clough and Johnston (1966) on a bilinear backbone: unloading
+// with the initial stiffness and reloading toward the largest previous excursion.
+func NewClough(stiffness, yieldForce, hardeningRatio float64) (*PeakOriented, error) {
+	return NewPeakOriented(bilinearParameters(stiffness, yieldForce, hardeningRatio))
+}
+
+// NewTakeda returns the modified Takeda model (Otani, 1974) on a bilinear backbone, whose unloading stiffness
+// k0 (uy/umax)^exponent decreases with the largest displacement.
+func NewTakeda(stiffness, yieldForce, hardeningRatio, unloadingExponent float64) (*PeakOriented, error) {
+	parameters := bilinearParameters(stiffness, yieldForce, hardeningRatio)
+	parameters.UnloadingExponent = unloadingExponent
+	return NewPeakOriented(parameters)
+}
+
+// NewPinching returns a peak-oriented model on a bilinear backbone that reloads through the pinching point at the
+// fraction pinchingDisplacement of the way to the targeted peak and the fraction pinchingForce of its force.
+func NewPinching(
+	stiffness, yieldForce, hardeningRatio, pinchingForce, pinchingDisplacement float64,
+) (*PeakOriented, error) {
+	parameters := bilinearParameters(stiffness, yieldForce, hardeningRatio)
+	parameters.PinchingForce, parameters.PinchingDisplacement = pinchingForce, pinchingDisplacement
+	return NewPeakOriented(parameters)
+}
+
+// IMKParameters define the peak-oriented Ibarra-Medina-Krawinkler model with cyclic deterioration.
+type IMKParameters struct {
+	Stiffness             float64 // kN/m
+	YieldForce            float64 // kN
+	HardeningRatio        float64
+	CappingDisplacement   float64 // m
+	PostCappingRatio      float64 // negative
+	ResidualRatio         float64
+	UltimateDisplacement  float64 // m
+	DeteriorationEnergy   float64 // hysteretic energy capacity relative to Fy*uy
+	DeteriorationExponent float64 // c, usually 1
+}
+
+// NewIMK returns the peak-oriented model of Ibarra, Medina and Krawinkler (2005). The deterioration parameter of
+// each excursion, beta = (Ei / (Et - sum Ej))^c, reduces the yield and post-capping strengths and the unloading
+// stiffness in proportion (1 - beta), and increases the targeted peak displacement in proportion (1 + beta). An
+// excursion whose energy reaches the remaining capacity (beta = 1) exhausts the material: it loses its strength in
+// both directions and its force unloads to zero with the last unloading stiffness.
+func NewIMK(parameters IMKParameters) (*PeakOriented, error) {
+	if parameters.DeteriorationEnergy <= 0 {
+		return nil, errors.New("deterioration energy must be positive")
+	}
+	return NewPeakOriented(PeakOrientedParameters{
+		Stiffness:             parameters.Stiffness,
+		YieldForce:            parameters.YieldForce,
+		HardeningRatio:        parameters.HardeningRatio,
+		CappingDisplacement:   parameters.CappingDisplacement,
+		PostCappingRatio:      parameters.PostCappingRatio,
+		ResidualRatio:         parameters.ResidualRatio,
+		UltimateDisplacement:  parameters.UltimateDisplacement,
+		PinchingForce:         1,
+		PinchingDisplacement:  1,
+		DeteriorationEnergy:   parameters.DeteriorationEnergy,
+		DeteriorationExponent: parameters.DeteriorationExponent,
+	})
+}
+
+// backbone returns the force and tangent stiffness of the backbone at the non-negative displacement u in the
+// loading direction d, in the frame of that direction.
+func (m *PeakOriented) backbone(u float64, d int, s *peakOrientedState) (float64, float64) {
+	p := m.parameters
+	if u >= p.UltimateDisplacement {
+		return 0, 0
+	}
+	k0 := p.Stiffness
+	fy := s.yieldForces[d]
+	f, k := k0*u, k0
+	if hardening := fy + p.HardeningRatio*k0*(u-fy/k0); hardening < f {
+		f, k = hardening, p.HardeningRatio*k0
+	}
+	if postCapping := s.capIntercepts[d] + p.PostCappingRatio*k0*u; postCapping < f {
+		f, k = postCapping, p.PostCappingRatio*k0
+	}
+	if residual := p.ResidualRatio * p.YieldForce; f < residual && k0*u >= residual {
+		f, k = residual, 0
+	}
+	if f < 0 {
+		f, k = 0, 0
+	}
+	return f, k
+}
+
+// unloadingStiffness returns the unloading stiffness of the state.
+func (m *PeakOriented) unloadingStiffness(s *peakOrientedState) float64 {
+	p := m.parameters
+	uy := p.YieldForce / p.Stiffness
+	maxDisplacement := math.Max(math.Max(s.peaks[0].u, -s.peaks[1].u), uy)
+	return p.Stiffness * math.Pow(uy/maxDisplacement, p.UnloadingExponent) * s.unloadingFactor
+}
+
+// reload returns the force and tangent stiffness of the reloading branch from zero force at zeroForce toward the
+// peak, in the frame of the loading direction d.
+func (m *PeakOriented) reload(u, zeroForce float64, peak point, d int, s *peakOrientedState) (float64, float64) {
+	p := m.parameters
+	if peak.u <= zeroForce {
+		// the residual displacement exceeds the peak: target the backbone a yield displacement beyond
+		peak.u = zeroForce + s.yieldForces[d]/p.Stiffness
+		peak.f, _ = m.backbone(peak.u, d, s)
+	}
+	if u >= peak.u {
+		return m.backbone(u, d, s)
+	}
+	pinch := point{zeroForce + p.PinchingDisplacement*(peak.u-zeroForce), p.PinchingForce * peak.f}
+	if peak.u <= s.yieldForces[d]/p.Stiffness {
+		// the direction has not yielded yet and is not pinched
+		pinch = peak
+	}
+	if u <= pinch.u {
+		slope := pinch.f / (pinch.u - zeroForce)
+		return slope * (u - zeroForce), slope
+	}
+	slope := (peak.f - pinch.f) / (peak.u - pinch.u)
+	return pinch.f + slope*(u-pinch.u), slope
+}
+
+func (m *PeakOriented) SetTrialDisplacement(u float64) (float64, float64) {
+	c := &m.committed
+	m.trial = *c
+	m.trial.crossed = false
+	du := u - c.u
+	if du == 0 {
+		return c.f, c.k
+	}
+	// the branches are evaluated in the frame of the loading direction
+	s, d := 1., 0
+	if du < 0 {
+		s, d = -1, 1
+	}
+	uc, fc, ut := s*c.u, s*c.f, s*u
+	peak := point{s * c.peaks[d].u, s * c.peaks[d].f}
+	zeroForce := s * c.zeroForce
+	ku := m.unloadingStiffness(c)
+
+	var f, k float64
+	if c.exhausted {
+		// no strength is left: a force opposing the loading unloads to zero, where it stays
+		f, k = fc+ku*(ut-uc), ku
+		if f > 0 {
+			f, k = 0, 0
+		}
+	} else if fc <= 0 {
+		zeroForce = uc - fc/ku
+		if ut <= zeroForce {
+			f, k = fc+ku*(ut-uc), ku
+		} else {
+			f, k = m.reload(ut, zeroForce, peak, d, c)
+			m.trial.zeroForce, m.trial.crossed = s*zeroForce, fc < 0
+		}
+	} else {
+		f, k = fc+ku*(ut-uc), ku
+		if ut > zeroForce {
+			if reloading, slope := m.reload(ut, zeroForce, peak, d, c); reloading < f {
+				f, k = reloading, slope
+			}
+		}
+	}
+	if ut > peak.u {
+		m.trial.peaks[d] = point{u, s * f}
+	}
+	energy := (c.f + s*f) / 2 * du
+	m.trial.u, m.trial.f, m.trial.k = u, s*f, k
+	m.trial.excursionEnergy += energy
+	m.trial.energy += energy
+	return s * f, k
+}
+
+func (m *PeakOriented) CommitState() {
+	t := &m.trial
+	p := m.parameters
+	if t.crossed && p.DeteriorationEnergy > 0 {
+		// the completed excursion deteriorates the excursion in the new loading direction
+		d := 0
+		if t.u < m.committed.u {
+			d = 1
+		}
+		capacity := p.DeteriorationEnergy * p.YieldForce * p.YieldForce / p.Stiffness
+		beta := 1.
+		if remaining := capacity - t.energy; remaining > 0 {
+			beta = math.Min(math.Pow(math.Max(t.excursionEnergy, 0)/remaining, p.DeteriorationExponent), 1)
+		}
+		if beta == 1 {
+			// the strength is lost in both directions; the unloading stiffness is kept so that the force unloads
+			t.exhausted = true
+			t.yieldForces = [2]float64{}
+			t.capIntercepts = [2]float64{}
+			t.crossed = false
+			m.committed = *t
+			return
+		}
+		t.yieldForces[d] *= 1 - beta
+		t.capIntercepts[d] *= 1 - beta
+		t.unloadingFactor *= 1 - beta
+		s := 1 - 2*float64(d)
+		t.peaks[d].u *= 1 + beta
+		force, _ := m.backbone(s*t.peaks[d].u, d, t)
+		t.peaks[d].f = s * force
+		t.excursionEnergy = 0
+	}
+	t.crossed = false
+	m.committed = *t
+}
+
+// Exhausted reports whether the cyclic deterioration has exhausted the hysteretic energy capacity, leaving the
+// material without strength.
+func (m *PeakOriented) Exhausted() bool {
+	return m.committed.exhausted
+}
+
+func (m *PeakOriented) InitialStiffness() float64 {
+	return m.parameters.Stiffness
+}
+
+func (m *PeakOriented) Clone() Material {
+	clone, _ := NewPeakOriented(m.parameters)
+	return clone
+}
@@ -0,0 +1,170 @@
+package hysteresis
+
+import (
+	"math"
+	"testing"
+
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// finite reports whether x is neither infinite nor NaN.
+func finite(x float64) bool {
+	return !math.IsInf(x, 0) && !math.IsNaN(x)
+}
+
+// loopEnergy returns the work of the forces along the displacements, starting from rest.
+func loopEnergy(displacements, forces []float64) float64 {
+	var energy, u, f float64
+	for i := range displacements {
+		energy += (f + forces[i]) / 2 * (displacements[i] - u)
+		u, f = displacements[i], forces[i]
+	}
+	return energy
+}
+
+func TestClough(t *testing.T) {
+	material, err := NewClough(1000, 10, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	load(material, path(1e-4, 0, 0.03, 0.02))
+	// unloading with the initial stiffness reaches zero force at 0.02 m
+	if f, k := material.SetTrialDisplacement(0.021); math.Abs(f-1) > 1e-6 || math.Abs(k-1000) > 1e-6 {
+		t.Errorf("Expected zero force with the initial stiffness, got %f and %f", f, k)
+	}
+	// reloading toward the unyielded negative peak (-0.01, -10)
+	load(material, path(1e-4, 0.02, 0.005))
+	if f, _ := material.SetTrialDisplacement(0.005); math.Abs(f+10*0.015/0.03) > 1e-6 {
+		t.Errorf("Expected reloading toward the negative yield point, got %f", f)
+	}
+	// reloading toward the previous positive peak (0.03, 10) from zero force at -0.01
+	load(material, path(1e-4, 0.005, -0.02, 0))
+	f, k := material.SetTrialDisplacement(0.01)
+	if zero := -0.02 + 10./1000; math.Abs(k-10/(0.03-zero)) > 1e-6 || math.Abs(f-k*(0.01-zero)) > 1e-6 {
+		t.Errorf("Expected reloading toward the positive peak, got %f and %f", f, k)
+	}
+}
+
+func TestTakeda(t *testing.T) {
+	material, _ := NewTakeda(1000, 10, 0.05, 0.4)
+	load(material, path(1e-4, 0, 0.04))
+	_, k := material.SetTrialDisplacement(0.039)
+	if expected := 1000 * math.Pow(0.01/0.04, 0.4); math.Abs(k-expected) > 1e-9 {
+		t.Errorf("Expected degraded unloading stiffness %f, got %f", expected, k)
+	}
+	clough, _ := NewClough(1000, 10, 0.05)
+	displacements := path(1e-4, 0, 0.04, -0.04, 0.04, -0.04)
+	takedaEnergy := loopEnergy(displacements, load(material.Clone(), displacements))
+	cloughEnergy := loopEnergy(displacements, load(clough, displacements))
+	if takedaEnergy >= cloughEnergy {
+		t.Errorf("Expected less dissipation with stiffness degradation, got %f and %f", takedaEnergy, cloughEnergy)
+	}
+}
+
+func TestPinching(t *testing.T) {
+	material, err := NewPinching(1000, 10, 0, 0.25, 0.5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// the first loading is not pinched
+	if f, _ := material.SetTrialDisplacement(0.005); math.Abs(f-5) > 1e-9 {
+		t.Errorf("Expected elastic first loading, got %f", f)
+	}
+	displacements := path(1e-4, 0, 0.03, -0.03, 0.03)
+	forces := load(material, displacements)
+	// reloading from zero force at -0.02 m goes through (0.005, 2.5)
+	if i := 900 + 349; math.Abs(displacements[i]-0.005) > 1e-9 || math.Abs(forces[i]-2.5) > 1e-6 {
+		t.Errorf("Expected the pinching point force 2.5 at 0.005, got %f at %f", forces[i], displacements[i])
+	}
+	clough, _ := NewClough(1000, 10, 0)
+	pinched, full := loopEnergy(displacements, forces), loopEnergy(displacements, load(clough, displacements))
+	if pinched >= full {
+		t.Errorf("Expected less dissipation with pinching, got %f and %f", pinched, full)
+	}
+}
+
+func TestIMK(t *testing.T) {
+	parameters := IMKParameters{
+		Stiffness: 1000, YieldForce: 10, HardeningRatio: 0.03, CappingDisplacement: 0.05, PostCappingRatio: -0.1,
+		ResidualRatio: 0.2, UltimateDisplacement: 0.2, DeteriorationEnergy: 50, DeteriorationExponent: 1,
+	}
+	material, err := NewIMK(parameters)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// the backbone
+	cases := []struct{ u, f float64 }{{0.05, 10 + 30*0.04}, {0.06, 11.2 - 1}, {0.15, 2}, {0.25, 0}}
+	for _, c := range cases {
+		fresh := material.Clone()
+		forces := load(fresh, path(1e-4, 0, c.u))
+		if f := forces[len(forces)-1]; math.Abs(f-c.f) > 1e-6 {
+			t.Errorf("Expected backbone force %f at %f, got %f", c.f, c.u, f)
+		}
+	}
+
+	// the strength at the peak of identical cycles decreases
+	var peaks []float64
+	for cycle := 0; cycle < 4; cycle++ {
+		forces := load(material, path(1e-4, 0, 0.03, -0.03, 0))
+		peaks = append(peaks, forces[299])
+	}
+	for i := 1; i < len(peaks); i++ {
+		if peaks[i] >= peaks[i-1] {
+			t.Errorf("Expected cyclic strength deterioration, got peak forces %v", peaks)
+			break
+		}
+	}
+	nonDeteriorating := parameters
+	nonDeteriorating.DeteriorationEnergy = 0
+	if _, err := NewIMK(nonDeteriorating); err == nil {
+		t.Errorf("Expected error for zero deterioration energy")
+	}
+	invalid := bilinearParameters(1000, 10, 0)
+	invalid.PinchingForce = 0
+	if _, err := NewPeakOriented(invalid); err == nil {
+		t.Errorf("Expected error for zero pinching force")
+	}
+}
+
+func TestIMKExhaustion(t *testing.T) {
+	material, err := NewIMK(IMKParameters{
+		Stiffness: 1000, YieldForce: 10, HardeningRatio: 0.03, CappingDisplacement: 0.05, PostCappingRatio: -0.1,
+		ResidualRatio: 0.2, UltimateDisplacement: 0.2, DeteriorationEnergy: 5, DeteriorationExponent: 1,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// the energy of the excursions exceeds the remaining capacity
+	for cycle := 0; cycle < 10 && !material.Exhausted(); cycle++ {
+		load(material, path(1e-4, 0, 0.08, -0.08, 0))
+	}
+	if !material.Exhausted() {
+		t.Fatalf("Expected the energy capacity to be exhausted")
+	}
+	// the force unloads to zero with a finite stiffness and stays there
+	for _, u := range path(1e-4, 0, 0.05, -0.05, 0) {
+		f, k := material.SetTrialDisplacement(u)
+		material.CommitState()
+		if !finite(f) || !finite(k) || k < 0 {
+			t.Fatalf("Expected finite force and stiffness at %f, got %f and %f", u, f, k)
+		}
+	}
+	if f, k := material.SetTrialDisplacement(0.1); f != 0 || k != 0 {
+		t.Errorf("Expected no strength left, got force %f and stiffness %f", f, k)
+	}
+
+	// an oscillator whose spring is exhausted during the motion drifts without errors
+	strong := ts.MotionData{Accelerations: make([]float64, len(testMotion.Accelerations)), TimeStep: testMotion.TimeStep}
+	for i, acceleration := range testMotion.Accelerations {
+		strong.Accelerations[i] = 10 * acceleration
+	}
+	oscillator, _ := NewSDOF(1, 0.05, material.Clone())
+	response, err := oscillator.Respond(strong)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !finite(response.PeakDisplacement) || response.Forces[len(response.Forces)-1] != 0 {
+		t.Errorf("Expected a finite peak displacement and no force left, got %f and %f",
+			response.PeakDisplacement, response.Forces[len(response.Forces)-1])
+	}
+}
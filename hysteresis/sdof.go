@@ -0,0 +1,122 @@
+package hysteresis
+
+import (
+	"errors"
+	"math"
+
+	"github.com/geoport/GoQuakeLib/internal/newmark"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+const gravity = 9.81 // m/s2
+
+// SDOF is a single-degree-of-freedom oscillator whose spring is a hysteretic material.
+type SDOF struct {
+	Mass     float64 // t
+	Damping  float64 // ratio of the critical damping at the initial stiffness
+	Material Material
+}
+
+// Response holds the time histories of an oscillator. The forces against the displacements are the
+// force-displacement loops of the spring.
+type Response struct {
+	Times                []float64
+	Displacements        []float64 // relative to the ground (cm)
+	Velocities           []float64 // relative to the ground (cm/s)
+	Accelerations        []float64 // absolute (g)
+	Forces               []float64 // restoring forces of the spring (kN)
+	PeakDisplacement     float64   // largest absolute displacement (cm)
+	ResidualDisplacement float64   // displacement at the end of the motion (cm)
+	// HystereticEnergy is the work of the restoring force less the strain energy recoverable at the initial
+	// stiffness at the end of the motion (kN*m).
+	HystereticEnergy float64
+}
+
+// NewSDOF returns the oscillator with the material and the period (s) at its initial stiffness.
+func NewSDOF(period, damping float64, material Material) (*SDOF, error) {
+	if period <= 0 {
+		return nil, errors.New("period must be positive")
+	}
+	if material == nil {
+		return nil, errors.New("material is nil")
+	}
+	omega := 2 * math.Pi / period
+	sdof := SDOF{Mass: material.InitialStiffness() / (omega * omega), Damping: damping, Material: material}
+	return &sdof, sdof.check()
+}
+
+func (o *SDOF) check() error {
+	if o.Mass <= 0 {
+		return errors.New("mass must be positive")
+	}
+	if o.Damping < 0 || o.Damping >= 1 {
+		return errors.New("damping ratio must be in [0, 1)")
+	}
+	if o.Material == nil {
+		return errors.New("material is nil")
+	}
+	return nil
+}
+
+// Period returns the period (s) of the oscillator at the initial stiffness of its material.
+func (o *SDOF) Period() float64 {
+	return 2 * math.Pi * math.Sqrt(o.Mass/o.Material.InitialStiffness())
+}
+
+// Respond returns the response of the oscillator, starting at rest, to the ground accelerations (g) of the motion.
+// The time steps are divided into newmark.Substeps steps of the initial period. The material of the oscillator
+// is not modified; the response is computed on a clone of it.
+func (o *SDOF) Respond(motion ts.MotionData) (*Response, error) {
+	if err := o.check(); err != nil {
+		return nil, err
+	}
+	accelerations, dt := motion.Accelerations, motion.TimeStep
+	if len(accelerations) < 2 {
+		return nil, errors.New("at least two acceleration samples are required")
+	}
+	if dt <= 0 {
+		return nil, errors.New("time step must be a positive number")
+	}
+	material := o.Material.Clone()
+	stiffness := material.InitialStiffness()
+	period := o.Period()
+	oscillator := newmark.Oscillator{
+		Mass:    o.Mass,
+		Damping: 2 * o.Damping * o.Mass * 2 * math.Pi / period,
+		Spring:  material,
+	}
+	substeps := newmark.Substeps(dt, period)
+	h := dt / float64(substeps)
+
+	n := len(accelerations)
+	response := Response{
+		Times:         make([]float64, n),
+		Displacements: make([]float64, n),
+		Velocities:    make([]float64, n),
+		Accelerations: make([]float64, n),
+		Forces:        make([]float64, n),
+	}
+	state := newmark.State{A: -accelerations[0] * gravity}
+	var work float64 // of the restoring force (kN*m)
+	for i := 1; i < n; i++ {
+		response.Times[i] = float64(i) * dt
+		start, end := accelerations[i-1]*gravity, accelerations[i]*gravity
+		for j := 0; j < substeps; j++ {
+			ground0 := start + (end-start)*float64(j)/float64(substeps)
+			ground1 := start + (end-start)*float64(j+1)/float64(substeps)
+			previous := state
+			if err := oscillator.Step(&state, ground0, ground1, h); err != nil {
+				return nil, err
+			}
+			work += (previous.F + state.F) / 2 * (state.U - previous.U)
+		}
+		response.Displacements[i] = state.U * 100
+		response.Velocities[i] = state.V * 100
+		response.Accelerations[i] = state.A/gravity + accelerations[i]
+		response.Forces[i] = state.F
+		response.PeakDisplacement = math.Max(response.PeakDisplacement, math.Abs(response.Displacements[i]))
+	}
+	response.ResidualDisplacement = response.Displacements[n-1]
+	response.HystereticEnergy = work - state.F*state.F/(2*stiffness)
+	return &response, nil
+}
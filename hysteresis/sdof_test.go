@@ -0,0 +1,94 @@
+package hysteresis
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+var testMotion = ts.MotionData{Accelerations: td.TestMotion["Accelerations"].([]float64), TimeStep: 0.005}
+
+func TestElasticSDOF(t *testing.T) {
+	material, _ := NewBilinear(1000, math.Inf(1), 0)
+	sdof, err := NewSDOF(0.5, 0.05, material)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	response, err := sdof.Respond(testMotion)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	elastic, _ := rs.CalcSDOFResponse(testMotion.Accelerations, testMotion.TimeStep, 0.5, 0.05)
+	if math.Abs(response.PeakDisplacement-elastic.PeakDisplacement) > 0.01*elastic.PeakDisplacement {
+		t.Errorf("Expected peak displacement %f, got %f", elastic.PeakDisplacement, response.PeakDisplacement)
+	}
+	var peakAcceleration float64
+	for _, acceleration := range response.Accelerations {
+		peakAcceleration = math.Max(peakAcceleration, math.Abs(acceleration))
+	}
+	if math.Abs(peakAcceleration-elastic.PeakAcceleration) > 0.01*elastic.PeakAcceleration {
+		t.Errorf("Expected peak acceleration %f, got %f", elastic.PeakAcceleration, peakAcceleration)
+	}
+	if math.Abs(response.HystereticEnergy) > 1e-6 {
+		t.Errorf("Expected no hysteretic energy, got %f", response.HystereticEnergy)
+	}
+}
+
+func TestInelasticSDOF(t *testing.T) {
+	options := rs.InelasticOptions{Model: "epp", Damping: 0.05}
+	spectra, _ := rs.CalcConstantStrengthSpectra(testMotion, []float64{1}, 4, options)
+	stiffness := 100 * math.Pow(2*math.Pi, 2)
+	yieldForce := 100 * spectra.YieldAccelerations[0] * gravity
+	material, _ := NewBilinear(stiffness, yieldForce, 0)
+	sdof := SDOF{Mass: 100, Damping: 0.05, Material: material}
+	response, err := sdof.Respond(testMotion)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := spectra.SpectralDisplacements[0]
+	if math.Abs(response.PeakDisplacement-expected) > 0.03*expected {
+		t.Errorf("Expected peak displacement %f, got %f", expected, response.PeakDisplacement)
+	}
+	if response.HystereticEnergy <= 0 {
+		t.Errorf("Expected hysteretic energy, got %f", response.HystereticEnergy)
+	}
+	if f, _ := material.SetTrialDisplacement(0.001); math.Abs(f-stiffness*0.001) > 1e-9 {
+		t.Errorf("Expected the material of the oscillator to be unloaded, got force %f", f)
+	}
+
+	// the loops of every model stay within their strength
+	clough, _ := NewClough(stiffness, yieldForce, 0.05)
+	takeda, _ := NewTakeda(stiffness, yieldForce, 0.05, 0.4)
+	pinching, _ := NewPinching(stiffness, yieldForce, 0.05, 0.3, 0.5)
+	boucWen, _ := NewBoucWen(stiffness, yieldForce, 0.05, 0.5, 0.5, 2)
+	imk, _ := NewIMK(IMKParameters{
+		Stiffness: stiffness, YieldForce: yieldForce, HardeningRatio: 0.05, CappingDisplacement: 0.1,
+		PostCappingRatio: -0.1, ResidualRatio: 0.2, UltimateDisplacement: 0.5, DeteriorationEnergy: 100,
+		DeteriorationExponent: 1,
+	})
+	for _, model := range []Material{clough, takeda, pinching, boucWen, imk} {
+		response, err := (&SDOF{Mass: 100, Damping: 0.05, Material: model}).Respond(testMotion)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		limit := yieldForce + 0.05*stiffness*response.PeakDisplacement/100
+		for i, force := range response.Forces {
+			if math.Abs(force) > 1.01*limit {
+				t.Fatalf("Expected forces within %f, got %f at %f s", limit, force, response.Times[i])
+			}
+		}
+		if response.HystereticEnergy <= 0 {
+			t.Errorf("Expected hysteretic energy, got %f", response.HystereticEnergy)
+		}
+	}
+
+	if _, err := NewSDOF(0, 0.05, material); err == nil {
+		t.Errorf("Expected error for zero period")
+	}
+	if _, err := sdof.Respond(ts.MotionData{Accelerations: []float64{0}, TimeStep: 0.01}); err == nil {
+		t.Errorf("Expected error for a single sample")
+	}
+}
@@ -0,0 +1,157 @@
+package hysteresis
+
+import (
+	"errors"
+
+	"github.com/geoport/GoQuakeLib/internal/newmark"
+	"github.com/geoport/GoQuakeLib/mdof"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// ShearBuilding is a shear building whose stories are hysteretic materials relating the story shears to the
+// inter-story drifts. The damping matrix is that of the elastic building with the initial story stiffnesses.
+type ShearBuilding struct {
+	Masses  []float64  // floor masses from the first floor up (t)
+	Stories []Material // story materials from the first story up
+	Damping mdof.DampingOptions
+}
+
+// NewShearBuilding returns the building with the floor masses (t) and story materials, from the bottom up.
+func NewShearBuilding(masses []float64, stories []Material, damping mdof.DampingOptions) (*ShearBuilding, error) {
+	building := ShearBuilding{Masses: append([]float64{}, masses...), Stories: stories, Damping: damping}
+	if _, err := building.elastic(); err != nil {
+		return nil, err
+	}
+	return &building, nil
+}
+
+// elastic checks the building and returns the linear building with the initial story stiffnesses.
+func (b *ShearBuilding) elastic() (*mdof.ShearBuilding, error) {
+	if len(b.Stories) != len(b.Masses) {
+		return nil, errors.New("there must be a story material for each floor")
+	}
+	stiffnesses := make([]float64, len(b.Stories))
+	for i, story := range b.Stories {
+		if story == nil {
+			return nil, errors.New("story material is nil")
+		}
+		stiffnesses[i] = story.InitialStiffness()
+	}
+	return mdof.NewShearBuilding(b.Masses, stiffnesses, b.Damping)
+}
+
+// Modes returns the modal properties of the building at the initial story stiffnesses.
+func (b *ShearBuilding) Modes() (*mdof.ModalProperties, error) {
+	elastic, err := b.elastic()
+	if err != nil {
+		return nil, err
+	}
+	return elastic.Modes, nil
+}
+
+// storySystem returns the floors of the building on the story materials, whose restoring forces are the differences
+// of the story shears.
+func storySystem(masses []float64, damping [][]float64, stories []Material) newmark.System {
+	n := len(masses)
+	return newmark.System{
+		Masses:  masses,
+		Damping: damping,
+		Resist: func(u []float64) ([]float64, [][]float64) {
+			forces := make([]float64, n)
+			stiffness := make([][]float64, n)
+			for i := range stiffness {
+				stiffness[i] = make([]float64, n)
+			}
+			for story, material := range stories {
+				drift := u[story]
+				if story > 0 {
+					drift -= u[story-1]
+				}
+				shear, k := material.SetTrialDisplacement(drift)
+				forces[story] += shear
+				stiffness[story][story] += k
+				if story > 0 {
+					forces[story-1] -= shear
+					stiffness[story-1][story-1] += k
+					stiffness[story-1][story] -= k
+					stiffness[story][story-1] -= k
+				}
+			}
+			return forces, stiffness
+		},
+		Commit: func() {
+			for _, material := range stories {
+				material.CommitState()
+			}
+		},
+	}
+}
+
+// Respond returns the response of the building, starting at rest, to the ground accelerations (g) of the motion.
+// The time steps are divided into newmark.Substeps steps of the shortest initial period. The story materials
+// of the building are not modified; the response is computed on clones of them. The fields are checked on each call,
+// so a building may be built as a literal or changed after NewShearBuilding.
+func (b *ShearBuilding) Respond(motion ts.MotionData) (*mdof.Response, error) {
+	elastic, err := b.elastic()
+	if err != nil {
+		return nil, err
+	}
+	accelerations, dt := motion.Accelerations, motion.TimeStep
+	if len(accelerations) < 2 {
+		return nil, errors.New("at least two acceleration samples are required")
+	}
+	if dt <= 0 {
+		return nil, errors.New("time step must be a positive number")
+	}
+	n := len(b.Masses)
+	stories := make([]Material, n)
+	for story, material := range b.Stories {
+		stories[story] = material.Clone()
+	}
+	system := storySystem(elastic.Masses, elastic.DampingMatrix(), stories)
+	periods := elastic.Modes.Periods
+	substeps := newmark.Substeps(dt, periods[len(periods)-1])
+	h := dt / float64(substeps)
+
+	response := mdof.Response{
+		Times:              make([]float64, len(accelerations)),
+		Displacements:      make([][]float64, n),
+		FloorAccelerations: make([][]float64, n),
+		Drifts:             make([][]float64, n),
+		StoryShears:        make([][]float64, n),
+	}
+	for floor := 0; floor < n; floor++ {
+		response.Displacements[floor] = make([]float64, len(accelerations))
+		response.FloorAccelerations[floor] = make([]float64, len(accelerations))
+		response.Drifts[floor] = make([]float64, len(accelerations))
+		response.StoryShears[floor] = make([]float64, len(accelerations))
+	}
+	state := newmark.NewSystemState(n, accelerations[0]*gravity)
+	for step := 1; step < len(accelerations); step++ {
+		response.Times[step] = float64(step) * dt
+		start, end := accelerations[step-1]*gravity, accelerations[step]*gravity
+		for j := 0; j < substeps; j++ {
+			ground0 := start + (end-start)*float64(j)/float64(substeps)
+			ground1 := start + (end-start)*float64(j+1)/float64(substeps)
+			if err := system.Step(&state, ground0, ground1, h); err != nil {
+				return nil, err
+			}
+		}
+		// the story shears are the restoring forces accumulated from the roof down
+		var shear float64
+		for floor := n - 1; floor >= 0; floor-- {
+			shear += state.F[floor]
+			response.StoryShears[floor][step] = shear
+		}
+		for floor := 0; floor < n; floor++ {
+			response.Displacements[floor][step] = state.U[floor] * 100
+			response.FloorAccelerations[floor][step] = state.A[floor]/gravity + accelerations[step]
+			response.Drifts[floor][step] = state.U[floor] * 100
+			if floor > 0 {
+				response.Drifts[floor][step] -= state.U[floor-1] * 100
+			}
+		}
+	}
+	response.BaseShears = response.StoryShears[0]
+	return &response, nil
+}
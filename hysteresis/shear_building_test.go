@@ -0,0 +1,94 @@
+package hysteresis
+
+import (
+	"math"
+	"testing"
+
+	"github.com/geoport/GoQuakeLib/mdof"
+)
+
+func maxAbs(values []float64) float64 {
+	var peak float64
+	for _, value := range values {
+		peak = math.Max(peak, math.Abs(value))
+	}
+	return peak
+}
+
+func TestElasticShearBuilding(t *testing.T) {
+	masses := []float64{100, 100, 80}
+	stiffnesses := []float64{60000, 50000, 40000}
+	stories := make([]Material, len(stiffnesses))
+	for i, stiffness := range stiffnesses {
+		stories[i], _ = NewBilinear(stiffness, math.Inf(1), 0)
+	}
+	building, err := NewShearBuilding(masses, stories, mdof.DefaultDampingOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	response, err := building.Respond(testMotion)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	elastic, _ := mdof.NewShearBuilding(masses, stiffnesses, mdof.DefaultDampingOptions())
+	expected, _ := elastic.TimeHistory(testMotion, mdof.DefaultAnalysisOptions())
+	for floor := range masses {
+		peak, expectedPeak := maxAbs(response.Displacements[floor]), maxAbs(expected.Displacements[floor])
+		if math.Abs(peak-expectedPeak) > 0.01*expectedPeak {
+			t.Errorf("Expected elastic displacement %f at floor %d, got %f", expectedPeak, floor+1, peak)
+		}
+		shear, expectedShear := maxAbs(response.StoryShears[floor]), maxAbs(expected.StoryShears[floor])
+		if math.Abs(shear-expectedShear) > 0.01*expectedShear {
+			t.Errorf("Expected elastic story shear %f at story %d, got %f", expectedShear, floor+1, shear)
+		}
+	}
+	if _, err := NewShearBuilding(masses, stories[:2], mdof.DefaultDampingOptions()); err == nil {
+		t.Errorf("Expected error for missing story materials")
+	}
+
+	// the fields are checked and used on each call
+	literal := &ShearBuilding{Masses: masses, Stories: stories}
+	if _, err := literal.Respond(testMotion); err == nil {
+		t.Errorf("Expected error for a building without damping ratios")
+	}
+	literal.Damping = mdof.DefaultDampingOptions()
+	literal.Masses = []float64{400, 400, 320}
+	modes, err := literal.Modes()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := 2 * elastic.Modes.Periods[0]; math.Abs(modes.Periods[0]-expected) > 1e-9*expected {
+		t.Errorf("Expected the period %f of the changed masses, got %f", expected, modes.Periods[0])
+	}
+	literal.Stories = []Material{stories[0], stories[1], nil}
+	if _, err := literal.Respond(testMotion); err == nil {
+		t.Errorf("Expected error for a nil story material")
+	}
+}
+
+func TestInelasticShearBuilding(t *testing.T) {
+	stiffness := 100 * math.Pow(2*math.Pi, 2)
+	material, _ := NewTakeda(stiffness, 0.1*100*gravity, 0.02, 0.4)
+	building, _ := NewShearBuilding([]float64{100}, []Material{material}, mdof.DefaultDampingOptions())
+	response, err := building.Respond(testMotion)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	sdof, _ := (&SDOF{Mass: 100, Damping: 0.05, Material: material}).Respond(testMotion)
+	if peak := maxAbs(response.Displacements[0]); math.Abs(peak-sdof.PeakDisplacement) > 0.01*sdof.PeakDisplacement {
+		t.Errorf("Expected the single story to match the oscillator displacement %f, got %f", sdof.PeakDisplacement, peak)
+	}
+
+	stories := []Material{material, material.Clone()}
+	building, _ = NewShearBuilding([]float64{100, 100}, stories, mdof.DefaultDampingOptions())
+	response, err = building.Respond(testMotion)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for story, shears := range response.StoryShears {
+		drift := maxAbs(response.Drifts[story]) / 100
+		if limit := 0.1*100*gravity + 0.02*stiffness*drift; maxAbs(shears) > 1.01*limit {
+			t.Errorf("Expected story shears within %f at story %d, got %f", limit, story+1, maxAbs(shears))
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package ida
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/geoport/GoQuakeLib/hysteresis"
+	"github.com/geoport/GoQuakeLib/internal/numeric"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+// Model is a structure whose engineering demand parameter (EDP) is computed under a ground motion. An error of the
+// analysis is taken as a dynamic instability, unless it occurs at the first intensity of a record. The records are
+// analyzed concurrently, so the model must be safe for concurrent use.
+type Model interface {
+	EDP(motion ts.MotionData) (float64, error)
+}
+
+// ModelFunc adapts a function to the Model interface.
+type ModelFunc func(motion ts.MotionData) (float64, error)
+
+func (f ModelFunc) EDP(motion ts.MotionData) (float64, error) {
+	return f(motion)
+}
+
+// SDOFModel takes the peak drift ratio of a hysteretic oscillator, its peak displacement over its height, as the EDP.
+type SDOFModel struct {
+	Oscillator *hysteresis.SDOF
+	Height     float64 // m
+}
+
+func (m SDOFModel) EDP(motion ts.MotionData) (float64, error) {
+	if m.Oscillator == nil {
+		return 0, errors.New("oscillator is nil")
+	}
+	if m.Height <= 0 {
+		return 0, errors.New("height must be positive")
+	}
+	response, err := m.Oscillator.Respond(motion)
+	if err != nil {
+		return 0, err
+	}
+	return response.PeakDisplacement / 100 / m.Height, nil
+}
+
+// BuildingModel takes the largest peak inter-story drift ratio of a nonlinear shear building as the EDP.
+type BuildingModel struct {
+	Building     *hysteresis.ShearBuilding
+	StoryHeights []float64 // m, from the first story up
+}
+
+func (m BuildingModel) EDP(motion ts.MotionData) (float64, error) {
+	if m.Building == nil {
+		return 0, errors.New("building is nil")
+	}
+	if len(m.StoryHeights) != len(m.Building.Masses) {
+		return 0, errors.New("there must be a height for each story")
+	}
+	response, err := m.Building.Respond(motion)
+	if err != nil {
+		return 0, err
+	}
+	var maxDrift float64
+	for story, drifts := range response.Drifts {
+		if m.StoryHeights[story] <= 0 {
+			return 0, errors.New("story heights must be positive")
+		}
+		maxDrift = math.Max(maxDrift, np.Max(np.Abs(drifts))/100/m.StoryHeights[story])
+	}
+	return maxDrift, nil
+}
+
+// Options controls the hunt-and-fill tracing of the IDA curves (Vamvatsikos and Cornell, 2004). The hunt increases the
+// intensity by a growing step until the structure collapses, the collapse intensity is then bracketed by bisection
+// to the tolerance, and the remaining runs fill the largest intensity gaps below it.
+type Options struct {
+	Measure       IntensityMeasure
+	InitialStep   float64 // first intensity and step of the hunt, in the units of the measure
+	StepIncrement float64 // increase of the hunt step after each run
+	MaxRuns       int     // analyses of each record
+	Tolerance     float64 // relative resolution of the collapse intensity
+	// CollapseEDP is the EDP, such as a drift ratio limit, at or above which the structure collapses, infinite if
+	// none.
+	CollapseEDP float64
+	// FlatlineRatio is the fraction of the elastic IDA slope (intensity over EDP of the first run) below which the
+	// slope from the previous point is a flatline and the structure collapses, 0 if none.
+	FlatlineRatio float64
+}
+
+// Point is an analysis of a record scaled to an intensity.
+type Point struct {
+	// Intensity is the intensity of the scaled record, which may differ from the targeted one by the tolerance of the
+	// scale factor of a ground motion parameter.
+	Intensity   float64
+	ScaleFactor float64
+	EDP         float64
+	Collapsed   bool
+}
+
+// Curve is the IDA curve of a record.
+type Curve struct {
+	Points []Point // in increasing order of intensity
+	// CollapseIntensity is the largest intensity without collapse below the lowest collapse intensity, infinite if
+	// the record does not collapse the structure.
+	CollapseIntensity float64
+}
+
+// Result holds the IDA curves of the records and their fractiles.
+type Result struct {
+	Curves  []Curve
+	Summary *Summary
+}
+
+// DefaultOptions returns the hunt-and-fill tracing of 12 runs in 5% damped spectral acceleration at 1 s, starting
+// from 0.1 g with steps growing by 0.05 g, with collapse at a 10% drift ratio or at 20% of the elastic slope.
+func DefaultOptions() Options {
+	return Options{
+		Measure:       IntensityMeasure{Type: "sa", Period: 1, Damping: 0.05},
+		InitialStep:   0.1,
+		StepIncrement: 0.05,
+		MaxRuns:       12,
+		Tolerance:     0.05,
+		CollapseEDP:   0.1,
+		FlatlineRatio: 0.2,
+	}
+}
+
+func (o Options) check() error {
+	if err := o.Measure.check(); err != nil {
+		return err
+	}
+	if o.InitialStep <= 0 || o.StepIncrement < 0 {
+		return errors.New("initial step must be positive and step increment must not be negative")
+	}
+	if o.MaxRuns < 1 {
+		return errors.New("number of runs must be positive")
+	}
+	if o.Tolerance <= 0 || o.Tolerance >= 1 {
+		return errors.New("tolerance must be in (0, 1)")
+	}
+	if o.CollapseEDP <= 0 {
+		return errors.New("collapse EDP must be positive")
+	}
+	if o.FlatlineRatio < 0 || o.FlatlineRatio >= 1 {
+		return errors.New("flatline ratio must be in [0, 1)")
+	}
+	return nil
+}
+
+// Run traces the IDA curves of the structure under the records in parallel, and summarizes them at intensities up
+// to the largest one within the range of every curve. See Summarize.
+func Run(motions []ts.MotionData, model Model, options Options) (*Result, error) {
+	if len(motions) == 0 {
+		return nil, errors.New("motions are empty")
+	}
+	if model == nil {
+		return nil, errors.New("model is nil")
+	}
+	if err := options.check(); err != nil {
+		return nil, err
+	}
+	for _, motion := range motions {
+		if len(motion.Accelerations) < 2 || motion.TimeStep <= 0 {
+			return nil, errors.New("motions must have at least two samples and a positive time step")
+		}
+	}
+	result := Result{Curves: make([]Curve, len(motions))}
+	errs := make([]error, len(motions))
+	numeric.ParallelFor(len(motions), func(record int) {
+		result.Curves[record], errs[record] = options.trace(motions[record], model)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the summary stops at the end of the shortest curve that does not collapse
+	upper := math.Inf(1)
+	var largest float64
+	for _, curve := range result.Curves {
+		last := curve.Points[len(curve.Points)-1].Intensity
+		largest = math.Max(largest, last)
+		if math.IsInf(curve.CollapseIntensity, 1) {
+			upper = math.Min(upper, last)
+		}
+	}
+	upper = math.Min(upper, largest)
+	intensities := make([]float64, summaryLevels)
+	for i := range intensities {
+		intensities[i] = upper * float64(i+1) / summaryLevels
+	}
+	summary, err := Summarize(result.Curves, intensities)
+	if err != nil {
+		return nil, err
+	}
+	result.Summary = summary
+	return &result, nil
+}
+
+const summaryLevels = 50
+
+// trace returns the IDA curve of the record.
+func (o Options) trace(motion ts.MotionData, model Model) (Curve, error) {
+	value, err := o.Measure.intensity(motion, 1)
+	if err != nil {
+		return Curve{}, err
+	}
+	if value <= 0 {
+		return Curve{}, errors.New("record has no intensity")
+	}
+	var points []Point
+	var elasticSlope float64
+	run := func(intensity float64) (bool, error) {
+		scaleFactor, achieved, err := o.Measure.scaleFactor(motion, value, intensity)
+		if err != nil {
+			return false, err
+		}
+		point := Point{Intensity: achieved, ScaleFactor: scaleFactor}
+		scaled := motion
+		scaled.Accelerations = np.MultiplyBy(motion.Accelerations, point.ScaleFactor)
+		edp, err := model.EDP(scaled)
+		if err != nil && len(points) == 0 {
+			return false, err
+		}
+		point.EDP = edp
+		point.Collapsed = err != nil || math.IsNaN(edp) || edp >= o.CollapseEDP
+		if len(points) == 0 {
+			if !point.Collapsed && edp > 0 {
+				elasticSlope = point.Intensity / edp
+			}
+		} else if previous := lastStable(points, point.Intensity); !point.Collapsed && edp > previous.EDP {
+			slope := (point.Intensity - previous.Intensity) / (edp - previous.EDP)
+			point.Collapsed = slope < o.FlatlineRatio*elasticSlope
+		}
+		points = append(points, point)
+		sort.Slice(points, func(i, j int) bool { return points[i].Intensity < points[j].Intensity })
+		return point.Collapsed, nil
+	}
+
+	// hunt
+	intensity, step := 0., o.InitialStep
+	collapsed := false
+	for len(points) < o.MaxRuns && !collapsed {
+		intensity += step
+		step += o.StepIncrement
+		if collapsed, err = run(intensity); err != nil {
+			return Curve{}, err
+		}
+	}
+	if !collapsed {
+		return Curve{Points: points, CollapseIntensity: math.Inf(1)}, nil
+	}
+	// bracket
+	stable, unstable := capacity(points)
+	for len(points) < o.MaxRuns && unstable-stable > o.Tolerance*unstable {
+		if _, err = run((stable + unstable) / 2); err != nil {
+			return Curve{}, err
+		}
+		stable, unstable = capacity(points)
+	}
+	// fill
+	for len(points) < o.MaxRuns {
+		var gap, start, previous float64
+		for _, point := range points {
+			if point.Intensity > stable {
+				break
+			}
+			if point.Intensity-previous > gap {
+				gap, start = point.Intensity-previous, previous
+			}
+			previous = point.Intensity
+		}
+		if gap == 0 {
+			break
+		}
+		if _, err = run(start + gap/2); err != nil {
+			return Curve{}, err
+		}
+		stable, _ = capacity(points)
+	}
+	return Curve{Points: points, CollapseIntensity: stable}, nil
+}
+
+// capacity returns the largest intensity of the points without collapse below the first collapse, or 0, and the
+// lowest intensity at which the structure collapses, or infinity.
+func capacity(points []Point) (float64, float64) {
+	var stable float64
+	for _, point := range points {
+		if point.Collapsed {
+			return stable, point.Intensity
+		}
+		stable = point.Intensity
+	}
+	return stable, math.Inf(1)
+}
+
+// lastStable returns the point without collapse of largest intensity below the intensity, or the origin.
+func lastStable(points []Point, intensity float64) Point {
+	var stable Point
+	for _, point := range points {
+		if point.Intensity >= intensity {
+			break
+		}
+		if !point.Collapsed {
+			stable = point
+		}
+	}
+	return stable
+}
@@ -0,0 +1,186 @@
+package ida
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/geoport/GoQuakeLib/hysteresis"
+	"github.com/geoport/GoQuakeLib/mdof"
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// testRecords returns the test motion, its reverse and its first half.
+func testRecords() []ts.MotionData {
+	n := len(testMotion.Accelerations)
+	reversed := make([]float64, n)
+	for i, acceleration := range testMotion.Accelerations {
+		reversed[n-1-i] = acceleration
+	}
+	return []ts.MotionData{
+		testMotion,
+		{Accelerations: reversed, TimeStep: testMotion.TimeStep},
+		{Accelerations: testMotion.Accelerations[:n/2], TimeStep: testMotion.TimeStep},
+	}
+}
+
+func checkCurve(t *testing.T, curve Curve, options Options) {
+	if len(curve.Points) == 0 || len(curve.Points) > options.MaxRuns {
+		t.Fatalf("Expected 1 to %d runs, got %d", options.MaxRuns, len(curve.Points))
+	}
+	if !sort.SliceIsSorted(curve.Points, func(i, j int) bool {
+		return curve.Points[i].Intensity < curve.Points[j].Intensity
+	}) {
+		t.Errorf("Expected points in increasing order of intensity")
+	}
+	for _, point := range curve.Points {
+		if point.Intensity <= curve.CollapseIntensity && point.Collapsed {
+			t.Errorf("Expected no collapse at %f below the collapse intensity %f", point.Intensity, curve.CollapseIntensity)
+		}
+	}
+}
+
+func TestRunScaling(t *testing.T) {
+	options := DefaultOptions()
+	options.Measure.Period = 0.5
+	options.CollapseEDP = math.Inf(1)
+	// the spectral acceleration of the scaled records is the intensity
+	model := ModelFunc(func(motion ts.MotionData) (float64, error) {
+		response, err := rs.CalcSDOFResponse(motion.Accelerations, motion.TimeStep, 0.5, 0.05)
+		return response.PeakAcceleration, err
+	})
+	result, err := Run(testRecords(), model, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, curve := range result.Curves {
+		checkCurve(t, curve, options)
+		if len(curve.Points) != options.MaxRuns || !math.IsInf(curve.CollapseIntensity, 1) {
+			t.Errorf("Expected the hunt to use all runs without collapse, got %d runs", len(curve.Points))
+		}
+		for _, point := range curve.Points {
+			if math.Abs(point.EDP-point.Intensity) > 1e-9*point.Intensity {
+				t.Errorf("Expected spectral acceleration %f of the scaled record, got %f", point.Intensity, point.EDP)
+			}
+		}
+		last := curve.Points[len(curve.Points)-1].Intensity
+		if expected := 12*0.1 + 66*0.05; math.Abs(last-expected) > 1e-9 {
+			t.Errorf("Expected the hunt to reach %f, got %f", expected, last)
+		}
+	}
+	for j, intensity := range result.Summary.Intensities {
+		if math.Abs(result.Summary.EDP50[j]-intensity) > 1e-9 {
+			t.Errorf("Expected median EDP %f, got %f", intensity, result.Summary.EDP50[j])
+		}
+	}
+	if !math.IsInf(result.Summary.Collapse50, 1) {
+		t.Errorf("Expected no median collapse intensity, got %f", result.Summary.Collapse50)
+	}
+
+	// the intensity of a ground motion parameter is that of the scaled record
+	options.Measure = IntensityMeasure{Type: "gmp", Field: "AriasIntensity"}
+	options.MaxRuns = 3
+	arias := ModelFunc(func(motion ts.MotionData) (float64, error) {
+		return gmpValue(motion, 1, "AriasIntensity"), nil
+	})
+	result, err = Run(testRecords()[:1], arias, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i, point := range result.Curves[0].Points {
+		target := 0.1*float64(i+1) + 0.05*float64(i*(i+1)/2)
+		if math.Abs(point.EDP-point.Intensity) > 1e-9*point.Intensity || math.Abs(point.Intensity-target) > 1e-3*target {
+			t.Errorf("Expected Arias intensity %f of the scaled record near %f, got %f", point.EDP, target, point.Intensity)
+		}
+	}
+}
+
+func TestRunCollapse(t *testing.T) {
+	options := DefaultOptions()
+	options.Measure = IntensityMeasure{Type: "pga"}
+	// the analysis does not converge above a PGA of 1 g
+	model := ModelFunc(func(motion ts.MotionData) (float64, error) {
+		pga, _ := options.Measure.intensity(motion, 1)
+		if pga > 1 {
+			return 0, errors.New("did not converge")
+		}
+		return 0.01 * pga, nil
+	})
+	result, err := Run(testRecords(), model, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, curve := range result.Curves {
+		checkCurve(t, curve, options)
+		stable, unstable := capacity(curve.Points)
+		if stable > 1+1e-9 || unstable <= 1 || unstable-stable > options.Tolerance*unstable {
+			t.Errorf("Expected the collapse intensity of 1 bracketed, got %f and %f", stable, unstable)
+		}
+		if curve.CollapseIntensity != stable || len(curve.Points) != options.MaxRuns {
+			t.Errorf("Expected all runs with collapse intensity %f, got %f", stable, curve.CollapseIntensity)
+		}
+	}
+	if s := result.Summary; s.Collapse16 > s.Collapse50 || s.Collapse50 > s.Collapse84 || s.Collapse84 > 1 {
+		t.Errorf("Expected ordered collapse fractiles below 1, got %f, %f and %f", s.Collapse16, s.Collapse50, s.Collapse84)
+	}
+
+	failing := ModelFunc(func(motion ts.MotionData) (float64, error) {
+		return 0, errors.New("invalid model")
+	})
+	if _, err := Run(testRecords(), failing, options); err == nil {
+		t.Errorf("Expected the error of the first run")
+	}
+	options.MaxRuns = 0
+	if _, err := Run(testRecords(), model, options); err == nil {
+		t.Errorf("Expected error for no runs")
+	}
+}
+
+func TestRunModels(t *testing.T) {
+	options := DefaultOptions()
+	options.MaxRuns = 8
+	material, _ := hysteresis.NewIMK(hysteresis.IMKParameters{
+		Stiffness: 100 * math.Pow(2*math.Pi, 2), YieldForce: 0.2 * 100 * 9.81, HardeningRatio: 0.03,
+		CappingDisplacement: 0.15, PostCappingRatio: -0.1, ResidualRatio: 0.2, UltimateDisplacement: 0.4,
+		DeteriorationEnergy: 50, DeteriorationExponent: 1,
+	})
+	oscillator, _ := hysteresis.NewSDOF(1, 0.05, material)
+	result, err := Run(testRecords(), SDOFModel{Oscillator: oscillator, Height: 3}, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, curve := range result.Curves {
+		checkCurve(t, curve, options)
+		if math.IsInf(curve.CollapseIntensity, 1) {
+			t.Errorf("Expected the deteriorating oscillator to collapse")
+		}
+	}
+	s := result.Summary
+	for j := range s.Intensities {
+		if s.EDP16[j] > s.EDP50[j] || s.EDP50[j] > s.EDP84[j] {
+			t.Errorf("Expected ordered EDP fractiles, got %f, %f and %f", s.EDP16[j], s.EDP50[j], s.EDP84[j])
+		}
+	}
+
+	stories := []hysteresis.Material{material, material.Clone()}
+	building, _ := hysteresis.NewShearBuilding([]float64{50, 50}, stories, mdof.DefaultDampingOptions())
+	options.MaxRuns = 3
+	result, err = Run(testRecords()[:1], BuildingModel{Building: building, StoryHeights: []float64{3, 3}}, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if points := result.Curves[0].Points; points[0].EDP <= 0 || points[1].EDP <= points[0].EDP {
+		t.Errorf("Expected drift ratios increasing with the intensity, got %+v", points)
+	}
+	if _, err := Run(testRecords()[:1], BuildingModel{Building: building}, options); err == nil {
+		t.Errorf("Expected error for missing story heights")
+	}
+	if _, err := (BuildingModel{StoryHeights: []float64{3, 3}}).EDP(testRecords()[0]); err == nil {
+		t.Errorf("Expected error for a nil building")
+	}
+	if _, err := (SDOFModel{Height: 3}).EDP(testRecords()[0]); err == nil {
+		t.Errorf("Expected error for a nil oscillator")
+	}
+}
@@ -0,0 +1,156 @@
+package ida
+
+import (
+	"errors"
+	"math"
+	"reflect"
+
+	gmp "github.com/geoport/GoQuakeLib/ground_motion_parameters"
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+// IntensityMeasure selects the intensity measure to which the records are scaled.
+type IntensityMeasure struct {
+	Type    string  // "sa" (spectral acceleration, g), "pga" (g) or "gmp" (a field of GMPData)
+	Period  float64 // period of "sa", usually the fundamental period of the structure (s)
+	Damping float64 // damping ratio of "sa"
+	Field   string  // GMPData field of "gmp" in gmpMeasures, such as "Pgv" or "AriasIntensity"
+}
+
+// gmpSpectra returns the 5% damped spectra of the motion used by the spectral ground motion parameters.
+func gmpSpectra(motion ts.MotionData) *rs.ResponseSpectraData {
+	return rs.ResponseSpectra(motion.Accelerations, motion.TimeStep, np.Arange(0, 4, 0.02), 0.05)
+}
+
+// gmpMeasures compute the ground motion parameters that increase with the scale factor of the motion, so that they
+// can be used as intensity measures. Only the requested parameter, and the spectra if it needs them, is computed.
+var gmpMeasures = map[string]func(p *gmp.GMPData, motion ts.MotionData){
+	"Pga":                      func(p *gmp.GMPData, motion ts.MotionData) { p.CalcPGA(motion) },
+	"Pgv":                      func(p *gmp.GMPData, motion ts.MotionData) { p.CalcPGV(motion) },
+	"Pgd":                      func(p *gmp.GMPData, motion ts.MotionData) { p.CalcPGD(motion) },
+	"SustainedMaxAcceleration": func(p *gmp.GMPData, motion ts.MotionData) { p.CalcSustainedMaxAcceleration(motion) },
+	"SustainedMaxVelocity":     func(p *gmp.GMPData, motion ts.MotionData) { p.CalcSustainedMaxVelocity(motion) },
+	"EffectiveDesignAcceleration": func(p *gmp.GMPData, motion ts.MotionData) {
+		p.CalcEffectiveDesignAcceleration(motion)
+	},
+	"A95":                        func(p *gmp.GMPData, motion ts.MotionData) { p.CalcA95(motion) },
+	"AriasIntensity":             func(p *gmp.GMPData, motion ts.MotionData) { p.CalcAriasIntensity(motion) },
+	"RmsAcceleration":            func(p *gmp.GMPData, motion ts.MotionData) { p.CalcRMSAcceleration(motion) },
+	"RmsVelocity":                func(p *gmp.GMPData, motion ts.MotionData) { p.CalcRMSVelocity(motion) },
+	"RmsDisplacement":            func(p *gmp.GMPData, motion ts.MotionData) { p.CalcRMSDisplacement(motion) },
+	"CharacteristicIntensity":    func(p *gmp.GMPData, motion ts.MotionData) { p.CalcCharacteristicIntensity(motion) },
+	"SpecificEnergyDensity":      func(p *gmp.GMPData, motion ts.MotionData) { p.CalcSpecificEnergyDensity(motion) },
+	"CumulativeAbsoluteVelocity": func(p *gmp.GMPData, motion ts.MotionData) { p.CalcCumulativeAbsoluteVelocity(motion) },
+	"HousnerIntensity": func(p *gmp.GMPData, motion ts.MotionData) {
+		p.CalcHousnerIntensity(gmpSpectra(motion))
+	},
+	"AccelerationSpectrumIntensity": func(p *gmp.GMPData, motion ts.MotionData) {
+		p.CalcAccelerationSpectrumIntensity(gmpSpectra(motion))
+	},
+	"VelocitySpectrumIntensity": func(p *gmp.GMPData, motion ts.MotionData) {
+		p.CalcVelocitySpectrumIntensity(gmpSpectra(motion))
+	},
+}
+
+func (m IntensityMeasure) check() error {
+	switch m.Type {
+	case "sa":
+		if m.Period <= 0 {
+			return errors.New("period of the spectral acceleration must be positive")
+		}
+		if m.Damping < 0 || m.Damping >= 1 {
+			return errors.New("damping ratio must be in [0, 1)")
+		}
+	case "pga":
+	case "gmp":
+		if _, ok := gmpMeasures[m.Field]; !ok {
+			return errors.New("field is not a ground motion parameter that increases with the scale factor")
+		}
+	default:
+		return errors.New("intensity measure not supported")
+	}
+	return nil
+}
+
+// intensity returns the intensity of the motion scaled by the factor.
+func (m IntensityMeasure) intensity(motion ts.MotionData, scaleFactor float64) (float64, error) {
+	switch m.Type {
+	case "sa":
+		response, err := rs.CalcSDOFResponse(motion.Accelerations, motion.TimeStep, m.Period, m.Damping)
+		if err != nil {
+			return 0, err
+		}
+		return scaleFactor * response.PeakAcceleration, nil
+	case "pga":
+		return scaleFactor * np.Max(np.Abs(motion.Accelerations)), nil
+	default:
+		return gmpValue(motion, scaleFactor, m.Field), nil
+	}
+}
+
+// scaleFactor returns the scale factor of the motion whose intensity is the target, with the intensity of the motion
+// scaled by it, given the intensity of the unscaled motion. The spectral acceleration and the peak ground acceleration
+// are proportional to the scale factor. The scale factor of a ground motion parameter is found by regula falsi on the
+// logarithms of the scale factor and intensity, exact in one step for parameters proportional to a power of the
+// scale factor, and by bisection where it does not progress, to a relative tolerance of the intensity.
+func (m IntensityMeasure) scaleFactor(motion ts.MotionData, value, target float64) (float64, float64, error) {
+	if m.Type != "gmp" {
+		return target / value, target, nil
+	}
+	const tolerance = 1e-3
+	const maxEvaluations = 60
+	type sample struct{ scale, value float64 }
+	// the bracket of the target, with a zero scale factor where it is not found yet
+	lower, upper := sample{}, sample{}
+	update := func(s sample) {
+		if s.value < target {
+			lower = s
+		} else {
+			upper = s
+		}
+	}
+	update(sample{1, value})
+	scale := target / value
+	for evaluation := 0; evaluation < maxEvaluations; evaluation++ {
+		achieved := gmpValue(motion, scale, m.Field)
+		if math.Abs(achieved-target) <= tolerance*target {
+			return scale, achieved, nil
+		}
+		update(sample{scale, achieved})
+		switch {
+		case lower.scale == 0:
+			scale = upper.scale / 2
+		case upper.scale == 0:
+			scale = lower.scale * 2
+		case upper.value <= lower.value:
+			return 0, 0, errors.New("ground motion parameter does not increase with the scale factor")
+		default:
+			logLower, logUpper := math.Log(lower.scale), math.Log(upper.scale)
+			fraction := math.Log(target/lower.value) / math.Log(upper.value/lower.value)
+			if fraction < 0.01 || fraction > 0.99 {
+				fraction = 0.5
+			}
+			scale = math.Exp(logLower + fraction*(logUpper-logLower))
+		}
+	}
+	return 0, 0, errors.New("scale factor of the ground motion parameter did not converge")
+}
+
+// gmpValue returns the ground motion parameter of the motion scaled by the factor.
+func gmpValue(motion ts.MotionData, scaleFactor float64, field string) float64 {
+	scaled := ts.MotionData{
+		Accelerations: np.MultiplyBy(motion.Accelerations, scaleFactor),
+		TimeStep:      motion.TimeStep,
+		AccUnit:       "g",
+	}
+	scaled.FromAcceleration()
+	scaled.Times = make([]float64, len(scaled.Accelerations))
+	for i := range scaled.Times {
+		scaled.Times[i] = float64(i) * scaled.TimeStep
+	}
+	parameters := gmp.GMPData{}
+	gmpMeasures[field](&parameters, scaled)
+	return reflect.ValueOf(parameters).FieldByName(field).Float()
+}
@@ -0,0 +1,67 @@
+package ida
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+var testMotion = ts.MotionData{Accelerations: td.TestMotion["Accelerations"].([]float64), TimeStep: 0.005}
+
+func TestIntensity(t *testing.T) {
+	sdof, _ := rs.CalcSDOFResponse(testMotion.Accelerations, testMotion.TimeStep, 0.8, 0.05)
+	cases := []struct {
+		measure IntensityMeasure
+		value   float64
+		// scale factor doubling the intensity
+		doubling float64
+	}{
+		{IntensityMeasure{Type: "sa", Period: 0.8, Damping: 0.05}, sdof.PeakAcceleration, 2},
+		{IntensityMeasure{Type: "pga"}, 0.16076, 2},
+		{IntensityMeasure{Type: "gmp", Field: "Pga"}, 0.16076, 2},
+		{IntensityMeasure{Type: "gmp", Field: "AriasIntensity"}, math.NaN(), math.Sqrt2},
+		{IntensityMeasure{Type: "gmp", Field: "CharacteristicIntensity"}, math.NaN(), math.Pow(2, 1/1.5)},
+		{IntensityMeasure{Type: "gmp", Field: "HousnerIntensity"}, math.NaN(), 2},
+	}
+	for _, c := range cases {
+		if err := c.measure.check(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		value, err := c.measure.intensity(testMotion, 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !math.IsNaN(c.value) && math.Abs(value-c.value) > 1e-5 {
+			t.Errorf("Expected %s %s intensity %f, got %f", c.measure.Type, c.measure.Field, c.value, value)
+		}
+		scaleFactor, achieved, err := c.measure.scaleFactor(testMotion, value, 2*value)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if math.Abs(scaleFactor-c.doubling) > 1e-3*c.doubling || math.Abs(achieved-2*value) > 1e-3*2*value {
+			t.Errorf("Expected %s %s scale factor %f, got %f with intensity %f of the target %f",
+				c.measure.Type, c.measure.Field, c.doubling, scaleFactor, achieved, 2*value)
+		}
+		// the achieved intensity is that of the scaled motion
+		if scaled, _ := c.measure.intensity(testMotion, scaleFactor); math.Abs(scaled-achieved) > 1e-12*achieved {
+			t.Errorf("Expected the achieved intensity %f, got %f", scaled, achieved)
+		}
+	}
+
+	invalid := []IntensityMeasure{
+		{Type: "sa"},
+		{Type: "sa", Period: 1, Damping: 1},
+		{Type: "gmp", Field: "AriasIntensityArray"},
+		{Type: "gmp", Field: "Unknown"},
+		{Type: "gmp", Field: "SignificantDuration"},
+		{Type: "pgv"},
+	}
+	for _, measure := range invalid {
+		if err := measure.check(); err == nil {
+			t.Errorf("Expected error for %+v", measure)
+		}
+	}
+}
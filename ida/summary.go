@@ -0,0 +1,90 @@
+package ida
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Summary holds the 16%, 50% and 84% fractiles of the IDA curves. The EDP fractiles at an intensity are infinite
+// where more records than the complement of the fraction collapse the structure, and the collapse intensity
+// fractiles are infinite where fewer records than the fraction collapse it.
+type Summary struct {
+	Intensities []float64
+	EDP16       []float64
+	EDP50       []float64
+	EDP84       []float64
+	Collapse16  float64
+	Collapse50  float64
+	Collapse84  float64
+}
+
+// EDPAt returns the EDP of the curve at the intensity, interpolated linearly between the points and the origin, and
+// infinite above the collapse intensity. It returns false above the largest intensity of a curve that does not
+// collapse.
+func (c Curve) EDPAt(intensity float64) (float64, bool) {
+	if intensity > c.CollapseIntensity {
+		return math.Inf(1), true
+	}
+	var previous Point
+	for _, point := range c.Points {
+		if point.Intensity >= intensity {
+			ratio := (intensity - previous.Intensity) / (point.Intensity - previous.Intensity)
+			return previous.EDP + ratio*(point.EDP-previous.EDP), true
+		}
+		previous = point
+	}
+	return 0, false
+}
+
+// Summarize returns the fractiles of the EDPs of the curves at the intensities and of their collapse intensities.
+// The intensities must be within the range of each curve.
+func Summarize(curves []Curve, intensities []float64) (*Summary, error) {
+	if len(curves) == 0 {
+		return nil, errors.New("curves are empty")
+	}
+	summary := Summary{
+		Intensities: append([]float64{}, intensities...),
+		EDP16:       make([]float64, len(intensities)),
+		EDP50:       make([]float64, len(intensities)),
+		EDP84:       make([]float64, len(intensities)),
+	}
+	values := make([]float64, len(curves))
+	for j, intensity := range intensities {
+		if intensity <= 0 {
+			return nil, errors.New("intensities must be positive")
+		}
+		for record, curve := range curves {
+			edp, ok := curve.EDPAt(intensity)
+			if !ok {
+				return nil, errors.New("intensity is beyond the range of a curve")
+			}
+			values[record] = edp
+		}
+		summary.EDP16[j], summary.EDP50[j], summary.EDP84[j] = fractiles(values)
+	}
+	for record, curve := range curves {
+		values[record] = curve.CollapseIntensity
+	}
+	summary.Collapse16, summary.Collapse50, summary.Collapse84 = fractiles(values)
+	return &summary, nil
+}
+
+// fractiles returns the 16%, 50% and 84% fractiles of the values, interpolated linearly between the sorted values.
+func fractiles(values []float64) (float64, float64, float64) {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	fractile := func(fraction float64) float64 {
+		position := fraction * float64(len(sorted)-1)
+		lower := int(math.Floor(position))
+		weight := position - float64(lower)
+		if weight == 0 {
+			return sorted[lower]
+		}
+		if math.IsInf(sorted[lower+1], 1) {
+			return math.Inf(1)
+		}
+		return sorted[lower] + weight*(sorted[lower+1]-sorted[lower])
+	}
+	return fractile(0.16), fractile(0.5), fractile(0.84)
+}
@@ -0,0 +1,59 @@
+package ida
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEDPAt(t *testing.T) {
+	curve := Curve{
+		Points: []Point{
+			{Intensity: 0.2, EDP: 0.01}, {Intensity: 0.4, EDP: 0.03}, {Intensity: 0.5, Collapsed: true},
+		},
+		CollapseIntensity: 0.4,
+	}
+	cases := []struct{ intensity, edp float64 }{{0.1, 0.005}, {0.3, 0.02}, {0.4, 0.03}, {0.45, math.Inf(1)}}
+	for _, c := range cases {
+		if edp, ok := curve.EDPAt(c.intensity); !ok || math.Abs(edp-c.edp) > 1e-12 && !math.IsInf(c.edp, 1) ||
+			math.IsInf(c.edp, 1) != math.IsInf(edp, 1) {
+			t.Errorf("Expected EDP %f at %f, got %f", c.edp, c.intensity, edp)
+		}
+	}
+	curve.CollapseIntensity = math.Inf(1)
+	curve.Points = curve.Points[:2]
+	if _, ok := curve.EDPAt(0.5); ok {
+		t.Errorf("Expected no EDP beyond the curve")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	var curves []Curve
+	for _, capacity := range []float64{0.4, 0.6, 0.8, 1, math.Inf(1)} {
+		curves = append(curves, Curve{
+			Points:            []Point{{Intensity: 0.4, EDP: 0.04 / math.Min(capacity, 2)}, {Intensity: 2, EDP: 0.1}},
+			CollapseIntensity: capacity,
+		})
+	}
+	summary, err := Summarize(curves, []float64{0.2, 0.7})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// EDPs at 0.2: 0.05, 0.0333, 0.025, 0.02 and 0.01
+	expected := []float64{0.01 + 0.64*0.01, 0.025, 0.0333333 + 0.36*(0.05-0.0333333)}
+	for i, edp := range []float64{summary.EDP16[0], summary.EDP50[0], summary.EDP84[0]} {
+		if math.Abs(edp-expected[i]) > 1e-6 {
+			t.Errorf("Expected EDP fractile %f, got %f", expected[i], edp)
+		}
+	}
+	// two records out of five collapse at 0.7
+	if !math.IsInf(summary.EDP84[1], 1) || math.IsInf(summary.EDP50[1], 1) {
+		t.Errorf("Expected the 84%% fractile only to collapse, got %f and %f", summary.EDP84[1], summary.EDP50[1])
+	}
+	if math.Abs(summary.Collapse16-0.528) > 1e-9 || summary.Collapse50 != 0.8 || !math.IsInf(summary.Collapse84, 1) {
+		t.Errorf("Expected collapse fractiles 0.528, 0.8 and infinite, got %f, %f and %f",
+			summary.Collapse16, summary.Collapse50, summary.Collapse84)
+	}
+	if _, err := Summarize(curves, []float64{3}); err == nil {
+		t.Errorf("Expected error beyond the curves")
+	}
+}
@@ -0,0 +1,51 @@
+package newmark
+
+// Bilinear is an elastoplastic spring with kinematic hardening. A zero hardening ratio is elastic-perfectly-plastic
+// and an infinite yield force is linear.
+type Bilinear struct {
+	stiffness, yieldForce, hardening float64
+	committed, trial                 bilinearState
+}
+
+type bilinearState struct {
+	u, f, k  float64
+	yielding bool
+}
+
+// NewBilinear returns the bilinear spring with the initial stiffness, yield force and post-yield to initial stiffness
+// ratio. The parameters are not checked.
+func NewBilinear(stiffness, yieldForce, hardeningRatio float64) *Bilinear {
+	s := Bilinear{stiffness: stiffness, yieldForce: yieldForce, hardening: hardeningRatio}
+	s.committed.k = stiffness
+	s.trial = s.committed
+	return &s
+}
+
+func (s *Bilinear) SetTrialDisplacement(u float64) (float64, float64) {
+	c := s.committed
+	f := c.f + s.stiffness*(u-c.u)
+	k := s.stiffness
+	yielding := false
+	offset := (1 - s.hardening) * s.yieldForce
+	if upper := s.hardening*s.stiffness*u + offset; f > upper {
+		f, k, yielding = upper, s.hardening*s.stiffness, true
+	} else if lower := s.hardening*s.stiffness*u - offset; f < lower {
+		f, k, yielding = lower, s.hardening*s.stiffness, true
+	}
+	s.trial = bilinearState{u: u, f: f, k: k, yielding: yielding}
+	return f, k
+}
+
+func (s *Bilinear) CommitState() {
+	s.committed = s.trial
+}
+
+// Yielding reports whether the committed state is on a post-yield branch.
+func (s *Bilinear) Yielding() bool {
+	return s.committed.yielding
+}
+
+// Parameters returns the initial stiffness, yield force and hardening ratio of the spring.
+func (s *Bilinear) Parameters() (float64, float64, float64) {
+	return s.stiffness, s.yieldForce, s.hardening
+}
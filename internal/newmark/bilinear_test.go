@@ -0,0 +1,32 @@
+package newmark
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBilinear(t *testing.T) {
+	spring := NewBilinear(1000, 10, 0.1)
+	cases := []struct {
+		u, f, k  float64
+		yielding bool
+	}{
+		{0.005, 5, 1000, false},
+		{0.02, 11, 100, true},
+		{0.01, 1, 1000, false},
+		{-0.01, -10, 100, true},
+	}
+	for _, c := range cases {
+		f, k := spring.SetTrialDisplacement(c.u)
+		spring.CommitState()
+		if math.Abs(f-c.f) > 1e-9 || math.Abs(k-c.k) > 1e-9 || spring.Yielding() != c.yielding {
+			t.Errorf("Expected force %f, stiffness %f and yielding %v at %f, got %f, %f and %v",
+				c.f, c.k, c.yielding, c.u, f, k, spring.Yielding())
+		}
+	}
+
+	linear := NewBilinear(1000, math.Inf(1), 0)
+	if f, k := linear.SetTrialDisplacement(10); f != 10000 || k != 1000 {
+		t.Errorf("Expected a linear spring with an infinite yield force, got %f and %f", f, k)
+	}
+}
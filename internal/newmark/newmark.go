@@ -0,0 +1,99 @@
+// Package newmark integrates the equations of motion of nonlinear structures under ground accelerations with the
+// average acceleration Newmark method and Newton-Raphson iterations (Chopra, Table 5.7.1). The units are those of
+// the caller.
+package newmark
+
+import (
+	"errors"
+	"math"
+)
+
+// Spring is a force-deformation relation. SetTrialDisplacement returns the restoring force and tangent stiffness at a
+// displacement reached from the last committed state, so it can be called repeatedly during the iterations of a time
+// step; CommitState accepts the last trial state.
+type Spring interface {
+	SetTrialDisplacement(u float64) (float64, float64)
+	CommitState()
+}
+
+const (
+	beta, gamma = 0.25, 0.5
+	// tolerance is the residual force relative to the effective load and restoring force at convergence.
+	tolerance      = 1e-10
+	maxIterations  = 50
+	maxSubdivision = 10
+	// maxStepRatio is the largest ratio of the time step to the shortest period.
+	maxStepRatio = 0.1
+)
+
+// errNotConverged is returned when the iterations do not converge after maxSubdivision halvings of a time step.
+var errNotConverged = errors.New("newton-raphson iterations did not converge")
+
+// Substeps returns the number of steps dividing the time step dt so that they do not exceed a tenth of the period.
+func Substeps(dt, period float64) int {
+	return int(math.Max(math.Ceil(dt/(maxStepRatio*period)), 1))
+}
+
+// Oscillator is a single-degree-of-freedom oscillator.
+type Oscillator struct {
+	Mass    float64
+	Damping float64 // viscous damping coefficient
+	Spring  Spring
+}
+
+// State is the displacement, velocity and acceleration relative to the ground, with the restoring force.
+type State struct {
+	U, V, A, F float64
+}
+
+// Step advances the state over the time step h, during which the ground acceleration goes linearly from ground0 to
+// ground1, and commits the spring. Steps that do not converge are halved up to maxSubdivision times.
+func (o *Oscillator) Step(state *State, ground0, ground1, h float64) error {
+	return o.step(state, ground0, ground1, h, 0)
+}
+
+func (o *Oscillator) step(state *State, ground0, ground1, h float64, depth int) error {
+	if next, ok := o.iterate(*state, ground1, h); ok {
+		*state = next
+		return nil
+	}
+	if depth == maxSubdivision {
+		return errNotConverged
+	}
+	middle := (ground0 + ground1) / 2
+	if err := o.step(state, ground0, middle, h/2, depth+1); err != nil {
+		return err
+	}
+	return o.step(state, middle, ground1, h/2, depth+1)
+}
+
+// iterate returns the state after the time step h and commits the spring if the iterations converge.
+func (o *Oscillator) iterate(state State, ground, h float64) (State, bool) {
+	m, c := o.Mass, o.Damping
+	a1 := m/(beta*h*h) + gamma/(beta*h)*c
+	a2 := m/(beta*h) + (gamma/beta-1)*c
+	a3 := (1/(2*beta)-1)*m + h*(gamma/(2*beta)-1)*c
+	pHat := -m*ground + a1*state.U + a2*state.V + a3*state.A
+
+	u := state.U
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		f, k := o.Spring.SetTrialDisplacement(u)
+		residual := pHat - f - a1*u
+		if math.Abs(residual) <= tolerance*(math.Abs(pHat)+math.Abs(f)) {
+			o.Spring.CommitState()
+			du := u - state.U
+			return State{
+				U: u,
+				V: gamma/(beta*h)*du + (1-gamma/beta)*state.V + h*(1-gamma/(2*beta))*state.A,
+				A: du/(beta*h*h) - state.V/(beta*h) - (1/(2*beta)-1)*state.A,
+				F: f,
+			}, true
+		}
+		kHat := k + a1
+		if kHat <= 0 || math.IsNaN(residual) || math.IsInf(residual, 0) {
+			return state, false
+		}
+		u += residual / kHat
+	}
+	return state, false
+}
@@ -0,0 +1,61 @@
+package newmark
+
+import (
+	"math"
+	"testing"
+)
+
+// nanSpring returns a force that is not a number.
+type nanSpring struct{}
+
+func (nanSpring) SetTrialDisplacement(float64) (float64, float64) { return math.NaN(), 1 }
+func (nanSpring) CommitState()                                    {}
+
+func TestSubsteps(t *testing.T) {
+	if Substeps(0.01, 1) != 1 || Substeps(0.01, 0.05) != 2 || Substeps(0.01, 0.01) != 10 {
+		t.Errorf("Expected steps of at most a tenth of the period")
+	}
+}
+
+func TestOscillator(t *testing.T) {
+	// undamped linear oscillator under a constant ground acceleration: u = -ag (1 - cos wt) / w2
+	omega := 2 * math.Pi
+	oscillator := Oscillator{Mass: 2, Spring: NewBilinear(2*omega*omega, math.Inf(1), 0)}
+	const ground, h = 0.5, 0.001
+	state := State{A: -ground}
+	for i := 1; i <= 1000; i++ {
+		if err := oscillator.Step(&state, ground, ground, h); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		time := float64(i) * h
+		expected := -ground * (1 - math.Cos(omega*time)) / (omega * omega)
+		if math.Abs(state.U-expected) > 1e-4*ground/(omega*omega) {
+			t.Fatalf("Expected displacement %f at %.3f s, got %f", expected, time, state.U)
+		}
+	}
+	if math.Abs(state.F-2*omega*omega*state.U) > 1e-9 {
+		t.Errorf("Expected the committed restoring force, got %f", state.F)
+	}
+
+	// elastic-perfectly-plastic: the force does not exceed the yield force
+	spring := NewBilinear(2*omega*omega, 0.5, 0)
+	oscillator = Oscillator{Mass: 2, Damping: 0.1, Spring: spring}
+	state = State{A: -ground}
+	for i := 1; i <= 1000; i++ {
+		if err := oscillator.Step(&state, ground, ground, h); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if math.Abs(state.F) > 0.5+1e-9 {
+			t.Fatalf("Expected the force bounded by the yield force, got %f", state.F)
+		}
+	}
+	if !spring.Yielding() {
+		t.Errorf("Expected the spring to yield")
+	}
+
+	failing := Oscillator{Mass: 1, Spring: nanSpring{}}
+	state = State{}
+	if err := failing.Step(&state, 1, 1, 0.01); err == nil {
+		t.Errorf("Expected error when the iterations do not converge")
+	}
+}
@@ -0,0 +1,116 @@
+package newmark
+
+import (
+	"math"
+
+	"github.com/geoport/GoQuakeLib/internal/numeric"
+)
+
+// System is a structure with lumped masses whose degrees of freedom all move with the ground.
+type System struct {
+	Masses  []float64
+	Damping [][]float64 // viscous damping matrix
+	// Resist returns the restoring forces and the tangent stiffness matrix at trial displacements reached from the
+	// last committed state. The returned slices belong to the caller.
+	Resist func(u []float64) ([]float64, [][]float64)
+	// Commit accepts the last trial state of the structure. It may be nil for linear structures.
+	Commit func()
+}
+
+// SystemState is the displacements, velocities and accelerations relative to the ground, with the restoring forces.
+type SystemState struct {
+	U, V, A, F []float64
+}
+
+// NewSystemState returns the state at rest of n degrees of freedom under the ground acceleration.
+func NewSystemState(n int, ground float64) SystemState {
+	state := SystemState{U: make([]float64, n), V: make([]float64, n), A: make([]float64, n), F: make([]float64, n)}
+	for i := range state.A {
+		state.A[i] = -ground
+	}
+	return state
+}
+
+// Step advances the state over the time step h, during which the ground acceleration goes linearly from ground0 to
+// ground1, and commits the structure. Steps that do not converge are halved up to maxSubdivision times.
+func (s *System) Step(state *SystemState, ground0, ground1, h float64) error {
+	return s.step(state, ground0, ground1, h, 0)
+}
+
+func (s *System) step(state *SystemState, ground0, ground1, h float64, depth int) error {
+	if next, ok := s.iterate(*state, ground1, h); ok {
+		*state = next
+		return nil
+	}
+	if depth == maxSubdivision {
+		return errNotConverged
+	}
+	middle := (ground0 + ground1) / 2
+	if err := s.step(state, ground0, middle, h/2, depth+1); err != nil {
+		return err
+	}
+	return s.step(state, middle, ground1, h/2, depth+1)
+}
+
+// iterate returns the state after the time step h and commits the structure if the iterations converge.
+func (s *System) iterate(state SystemState, ground, h float64) (SystemState, bool) {
+	n := len(s.Masses)
+	c := s.Damping
+	inertia := make([]float64, n)
+	damping := make([]float64, n)
+	for i := 0; i < n; i++ {
+		inertia[i] = state.U[i]/(beta*h*h) + state.V[i]/(beta*h) + (1/(2*beta)-1)*state.A[i]
+		damping[i] = gamma/(beta*h)*state.U[i] + (gamma/beta-1)*state.V[i] + h*(gamma/(2*beta)-1)*state.A[i]
+	}
+	pHat := make([]float64, n)
+	for i := 0; i < n; i++ {
+		pHat[i] = s.Masses[i] * (inertia[i] - ground)
+		for j := 0; j < n; j++ {
+			pHat[i] += c[i][j] * damping[j]
+		}
+	}
+
+	u := append([]float64{}, state.U...)
+	residual := make([]float64, n)
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		f, kHat := s.Resist(u)
+		var residualNorm, scale float64
+		for i := 0; i < n; i++ {
+			residual[i] = pHat[i] - f[i] - s.Masses[i]/(beta*h*h)*u[i]
+			for j := 0; j < n; j++ {
+				residual[i] -= gamma / (beta * h) * c[i][j] * u[j]
+			}
+			residualNorm = math.Max(residualNorm, math.Abs(residual[i]))
+			scale = math.Max(scale, math.Abs(pHat[i])+math.Abs(f[i]))
+		}
+		if residualNorm <= tolerance*scale {
+			if s.Commit != nil {
+				s.Commit()
+			}
+			next := SystemState{U: u, V: make([]float64, n), A: make([]float64, n), F: f}
+			for i := 0; i < n; i++ {
+				du := u[i] - state.U[i]
+				next.V[i] = gamma/(beta*h)*du + (1-gamma/beta)*state.V[i] + h*(1-gamma/(2*beta))*state.A[i]
+				next.A[i] = du/(beta*h*h) - state.V[i]/(beta*h) - (1/(2*beta)-1)*state.A[i]
+			}
+			return next, true
+		}
+		if math.IsNaN(residualNorm) || math.IsInf(residualNorm, 0) {
+			return state, false
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				kHat[i][j] += gamma / (beta * h) * c[i][j]
+			}
+			kHat[i][i] += s.Masses[i] / (beta * h * h)
+		}
+		du, err := numeric.Solve(kHat, residual)
+		if err != nil {
+			return state, false
+		}
+		for i := range u {
+			u[i] += du[i]
+		}
+	}
+	return state, false
+}
@@ -0,0 +1,49 @@
+package newmark
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSystem(t *testing.T) {
+	// a single degree of freedom matches the oscillator
+	omega := 2 * math.Pi
+	spring := NewBilinear(2*omega*omega, 0.5, 0.05)
+	oscillator := Oscillator{Mass: 2, Damping: 0.3, Spring: NewBilinear(2*omega*omega, 0.5, 0.05)}
+	system := System{
+		Masses:  []float64{2},
+		Damping: [][]float64{{0.3}},
+		Resist: func(u []float64) ([]float64, [][]float64) {
+			f, k := spring.SetTrialDisplacement(u[0])
+			return []float64{f}, [][]float64{{k}}
+		},
+		Commit: spring.CommitState,
+	}
+	state := State{A: -0.5}
+	systemState := NewSystemState(1, 0.5)
+	for i := 1; i <= 1000; i++ {
+		ground := 0.5 * math.Sin(float64(i)*0.01)
+		previous := 0.5 * math.Sin(float64(i-1)*0.01)
+		if err := oscillator.Step(&state, previous, ground, 0.01); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := system.Step(&systemState, previous, ground, 0.01); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if math.Abs(state.U-systemState.U[0]) > 1e-9 || math.Abs(state.F-systemState.F[0]) > 1e-9 {
+			t.Fatalf("Expected the response of the oscillator %f, got %f", state.U, systemState.U[0])
+		}
+	}
+
+	failing := System{
+		Masses:  []float64{1},
+		Damping: [][]float64{{0}},
+		Resist: func(u []float64) ([]float64, [][]float64) {
+			return []float64{math.NaN()}, [][]float64{{1}}
+		},
+	}
+	rest := NewSystemState(1, 0)
+	if err := failing.Step(&rest, 1, 1, 0.01); err == nil {
+		t.Errorf("Expected error when the iterations do not converge")
+	}
+}
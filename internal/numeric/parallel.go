@@ -0,0 +1,33 @@
+// Package numeric holds the numerical helpers shared by the analysis packages.
+package numeric
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelFor calls task for each index from 0 to n-1 on a pool of GOMAXPROCS workers and returns when all calls
+// have returned.
+func ParallelFor(n int, task func(j int)) {
+	jobs := make(chan int, n)
+	for j := 0; j < n; j++ {
+		jobs <- j
+	}
+	close(jobs)
+
+	numWorkers := runtime.GOMAXPROCS(0)
+	if numWorkers > n {
+		numWorkers = n
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				task(j)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,18 @@
+package numeric
+
+import "testing"
+
+func TestParallelFor(t *testing.T) {
+	results := make([]int, 100)
+	ParallelFor(len(results), func(j int) {
+		results[j] = j * j
+	})
+	for j, result := range results {
+		if result != j*j {
+			t.Fatalf("Expected %d at %d, got %d", j*j, j, result)
+		}
+	}
+	ParallelFor(0, func(int) {
+		t.Errorf("Expected no calls without indices")
+	})
+}
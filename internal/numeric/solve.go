@@ -0,0 +1,52 @@
+package numeric
+
+import (
+	"errors"
+	"math"
+)
+
+// Solve returns the solution of the linear system by Gaussian elimination with partial pivoting. The arguments are
+// not modified. An error is returned if the matrix is singular or not finite.
+func Solve(matrix [][]float64, rhs []float64) ([]float64, error) {
+	n := len(rhs)
+	if len(matrix) != n {
+		return nil, errors.New("matrix and right-hand side must have the same number of rows")
+	}
+	a := make([][]float64, n)
+	b := append([]float64{}, rhs...)
+	for i := range a {
+		if len(matrix[i]) != n {
+			return nil, errors.New("matrix must be square")
+		}
+		a[i] = append([]float64{}, matrix[i]...)
+	}
+	for column := 0; column < n; column++ {
+		pivot := column
+		for row := column + 1; row < n; row++ {
+			if math.Abs(a[row][column]) > math.Abs(a[pivot][column]) {
+				pivot = row
+			}
+		}
+		if value := a[pivot][column]; value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, errors.New("matrix is singular")
+		}
+		a[column], a[pivot] = a[pivot], a[column]
+		b[column], b[pivot] = b[pivot], b[column]
+		for row := column + 1; row < n; row++ {
+			factor := a[row][column] / a[column][column]
+			for j := column; j < n; j++ {
+				a[row][j] -= factor * a[column][j]
+			}
+			b[row] -= factor * b[column]
+		}
+	}
+	solution := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := b[i]
+		for j := i + 1; j < n; j++ {
+			sum -= a[i][j] * solution[j]
+		}
+		solution[i] = sum / a[i][i]
+	}
+	return solution, nil
+}
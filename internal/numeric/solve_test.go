@@ -0,0 +1,35 @@
+package numeric
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	// the first pivot is zero
+	matrix := [][]float64{{0, 2, 1}, {1, 1, 0}, {3, 0, 1}}
+	rhs := []float64{4, 3, 5}
+	solution, err := Solve(matrix, rhs)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i := range matrix {
+		var product float64
+		for j := range matrix {
+			product += matrix[i][j] * solution[j]
+		}
+		if math.Abs(product-rhs[i]) > 1e-12 {
+			t.Errorf("Expected %f in row %d, got %f", rhs[i], i, product)
+		}
+	}
+	if matrix[0][0] != 0 || rhs[0] != 4 {
+		t.Errorf("Expected the arguments unchanged")
+	}
+
+	if _, err := Solve([][]float64{{1, 2}, {2, 4}}, []float64{1, 2}); err == nil {
+		t.Errorf("Expected error for a singular matrix")
+	}
+	if _, err := Solve([][]float64{{1, 2}}, []float64{1, 2}); err == nil {
+		t.Errorf("Expected error for mismatched dimensions")
+	}
+}
@@ -0,0 +1,80 @@
+package mdof
+
+import (
+	"math"
+	"sort"
+)
+
+// jacobiEigen returns the eigenvalues of the symmetric matrix in ascending order with the eigenvectors as the
+// columns of the second result, computed with the cyclic Jacobi method. The matrix is not modified.
+func jacobiEigen(matrix [][]float64) ([]float64, [][]float64) {
+	n := len(matrix)
+	a := make([][]float64, n)
+	v := make([][]float64, n)
+	var norm float64
+	for i := range a {
+		a[i] = append([]float64{}, matrix[i]...)
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+		for _, value := range a[i] {
+			norm += value * value
+		}
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		var off float64
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += a[p][q] * a[p][q]
+			}
+		}
+		if off <= 1e-30*norm {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				// the rotation that zeroes a[p][q] (Numerical Recipes, Section 11.1)
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return a[order[i]][order[i]] < a[order[j]][order[j]] })
+	values := make([]float64, n)
+	vectors := make([][]float64, n)
+	for i := range vectors {
+		vectors[i] = make([]float64, n)
+	}
+	for column, index := range order {
+		values[column] = a[index][index]
+		for row := 0; row < n; row++ {
+			vectors[row][column] = v[row][index]
+		}
+	}
+	return values, vectors
+}
@@ -0,0 +1,44 @@
+package mdof
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJacobiEigen(t *testing.T) {
+	matrix := [][]float64{
+		{4, -2, 0, 1, 0.5},
+		{-2, 5, -1, 0, 0},
+		{0, -1, 3, -1, 0.2},
+		{1, 0, -1, 6, -2},
+		{0.5, 0, 0.2, -2, 2},
+	}
+	values, vectors := jacobiEigen(matrix)
+	for mode, value := range values {
+		if mode > 0 && value < values[mode-1] {
+			t.Errorf("Expected ascending eigenvalues, got %v", values)
+		}
+		var norm float64
+		for i := range matrix {
+			var product float64
+			for j := range matrix {
+				product += matrix[i][j] * vectors[j][mode]
+			}
+			if math.Abs(product-value*vectors[i][mode]) > 1e-10 {
+				t.Errorf("Expected eigenpair %d to satisfy A v = lambda v", mode)
+			}
+			norm += vectors[i][mode] * vectors[i][mode]
+		}
+		if math.Abs(norm-1) > 1e-12 {
+			t.Errorf("Expected unit eigenvector %d, got norm %f", mode, norm)
+		}
+	}
+	if matrix[0][1] != -2 {
+		t.Errorf("Expected the matrix not to be modified")
+	}
+
+	values, _ = jacobiEigen([][]float64{{2, -1}, {-1, 2}})
+	if math.Abs(values[0]-1) > 1e-14 || math.Abs(values[1]-3) > 1e-14 {
+		t.Errorf("Expected eigenvalues 1 and 3, got %v", values)
+	}
+}
@@ -0,0 +1,120 @@
+package mdof
+
+import (
+	"errors"
+	"math"
+
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// FloorSpectraOptions controls the floor response spectra of a shear building.
+type FloorSpectraOptions struct {
+	Analysis    AnalysisOptions
+	Damping     float64 // damping ratio of the equipment
+	Broadening  float64 // relative peak broadening, 0.15 for +-15%
+	Combination string  // "envelope" or "mean" of the spectra of the records
+}
+
+// FloorSpectraData holds the spectral accelerations (g) of the floors, indexed by floor and period.
+type FloorSpectraData struct {
+	Periods       []float64
+	Spectra       [][]float64   // combined and broadened
+	Unbroadened   [][]float64   // combined
+	RecordSpectra [][][]float64 // indexed by record, floor and period
+}
+
+// DefaultFloorSpectraOptions returns the envelope of the 5% damped spectra broadened by +-15%, computed with the
+// direct Newmark integration of the building.
+func DefaultFloorSpectraOptions() FloorSpectraOptions {
+	return FloorSpectraOptions{
+		Analysis: DefaultAnalysisOptions(), Damping: 0.05, Broadening: 0.15, Combination: "envelope",
+	}
+}
+
+// FloorSpectra returns the floor response spectra of the building excited by each motion. The spectra of the records
+// are combined and their peaks broadened as in ASCE 4: the broadened spectral acceleration at period T is the
+// largest one between (1-b)T and (1+b)T. The periods must be increasing.
+func (b *ShearBuilding) FloorSpectra(
+	motions []ts.MotionData, periods []float64, options FloorSpectraOptions,
+) (*FloorSpectraData, error) {
+	if len(motions) == 0 {
+		return nil, errors.New("motions are empty")
+	}
+	if len(periods) == 0 {
+		return nil, errors.New("periods are empty")
+	}
+	for j, period := range periods {
+		if period < 0 || (j > 0 && period <= periods[j-1]) {
+			return nil, errors.New("periods must be non-negative and increasing")
+		}
+	}
+	if options.Damping < 0 || options.Damping >= 1 {
+		return nil, errors.New("damping ratio must be in [0, 1)")
+	}
+	if options.Broadening < 0 || options.Broadening >= 1 {
+		return nil, errors.New("broadening must be in [0, 1)")
+	}
+	if options.Combination != "envelope" && options.Combination != "mean" {
+		return nil, errors.New("combination not supported")
+	}
+
+	numFloors := b.NumFloors()
+	spectra := FloorSpectraData{
+		Periods:       append([]float64{}, periods...),
+		Spectra:       make([][]float64, numFloors),
+		Unbroadened:   make([][]float64, numFloors),
+		RecordSpectra: make([][][]float64, len(motions)),
+	}
+	for floor := range spectra.Unbroadened {
+		spectra.Unbroadened[floor] = make([]float64, len(periods))
+	}
+	for record, motion := range motions {
+		response, err := b.TimeHistory(motion, options.Analysis)
+		if err != nil {
+			return nil, err
+		}
+		spectra.RecordSpectra[record] = make([][]float64, numFloors)
+		for floor, accelerations := range response.FloorAccelerations {
+			floorSpectra := rs.ResponseSpectra(accelerations, motion.TimeStep, periods, options.Damping)
+			spectra.RecordSpectra[record][floor] = floorSpectra.SpectralAccelerations
+			for j, value := range floorSpectra.SpectralAccelerations {
+				if options.Combination == "envelope" {
+					spectra.Unbroadened[floor][j] = math.Max(spectra.Unbroadened[floor][j], value)
+				} else {
+					spectra.Unbroadened[floor][j] += value / float64(len(motions))
+				}
+			}
+		}
+	}
+	for floor, values := range spectra.Unbroadened {
+		broadened, err := broaden(periods, values, options.Broadening)
+		if err != nil {
+			return nil, err
+		}
+		spectra.Spectra[floor] = broadened
+	}
+	return &spectra, nil
+}
+
+// broaden returns the largest spectral value between (1-b)T and (1+b)T of each period T, including the values
+// interpolated at the ends of the window within the range of the periods.
+func broaden(periods, values []float64, broadening float64) ([]float64, error) {
+	broadened := make([]float64, len(periods))
+	last := periods[len(periods)-1]
+	for j, period := range periods {
+		lower := math.Max((1-broadening)*period, periods[0])
+		upper := math.Min((1+broadening)*period, last)
+		ends, err := rs.InterpolateLogLog(periods, values, []float64{lower, upper})
+		if err != nil {
+			return nil, err
+		}
+		broadened[j] = math.Max(ends[0], ends[1])
+		for k, other := range periods {
+			if other >= lower && other <= upper {
+				broadened[j] = math.Max(broadened[j], values[k])
+			}
+		}
+	}
+	return broadened, nil
+}
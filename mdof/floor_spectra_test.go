@@ -0,0 +1,86 @@
+package mdof
+
+import (
+	"math"
+	"testing"
+
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+func TestFloorSpectra(t *testing.T) {
+	building, _ := NewShearBuilding([]float64{300, 250, 200}, []float64{4e5, 3e5, 2e5}, DefaultDampingOptions())
+	scaled := ts.MotionData{Accelerations: make([]float64, len(testMotion.Accelerations)), TimeStep: testMotion.TimeStep}
+	for i, acc := range testMotion.Accelerations {
+		scaled.Accelerations[i] = 0.5 * acc
+	}
+	motions := []ts.MotionData{testMotion, scaled}
+	periods, _ := rs.LogPeriods(0.02, 2, 60, true)
+
+	envelope, err := building.FloorSpectra(motions, periods, DefaultFloorSpectraOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	options := DefaultFloorSpectraOptions()
+	options.Combination = "mean"
+	mean, _ := building.FloorSpectra(motions, periods, options)
+
+	roof := building.NumFloors() - 1
+	for j := range periods {
+		if math.Abs(envelope.Unbroadened[roof][j]-envelope.RecordSpectra[0][roof][j]) > 1e-12 {
+			t.Fatalf("Expected the envelope to be the spectra of the stronger record")
+		}
+		if math.Abs(mean.Unbroadened[roof][j]-0.75*envelope.Unbroadened[roof][j]) > 1e-9 {
+			t.Fatalf("Expected the mean of the linear responses to be 75%% of the envelope")
+		}
+		if envelope.Spectra[roof][j] < envelope.Unbroadened[roof][j] {
+			t.Fatalf("Expected broadened spectra above the unbroadened spectra")
+		}
+	}
+
+	// the peak at the fundamental period is broadened over +-15%
+	fundamental := building.Modes.Periods[0]
+	peak, peakIndex := 0., 0
+	for j, period := range periods {
+		if period > 0.8*fundamental && period < 1.2*fundamental && envelope.Unbroadened[roof][j] > peak {
+			peak, peakIndex = envelope.Unbroadened[roof][j], j
+		}
+	}
+	for j, period := range periods {
+		if math.Abs(period-periods[peakIndex]) <= 0.14*period && envelope.Spectra[roof][j] < peak {
+			t.Errorf("Expected the peak %f at %.3f s, got %f", peak, period, envelope.Spectra[roof][j])
+		}
+	}
+
+	if zpa := envelope.Unbroadened[roof][0]; math.Abs(zpa-maxAbsFloorAcceleration(t, building, testMotion, roof)) > 1e-12 {
+		t.Errorf("Expected zero period acceleration equal to the peak floor acceleration, got %f", zpa)
+	}
+	options.Combination = "median"
+	if _, err := building.FloorSpectra(motions, periods, options); err == nil {
+		t.Errorf("Expected error for an unsupported combination")
+	}
+}
+
+func maxAbsFloorAcceleration(t *testing.T, building *ShearBuilding, motion ts.MotionData, floor int) float64 {
+	response, err := building.TimeHistory(motion, DefaultAnalysisOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return maxAbs(response.FloorAccelerations[floor])
+}
+
+func TestBroaden(t *testing.T) {
+	periods := []float64{0.5, 0.8, 0.9, 1, 1.1, 1.2, 2}
+	values := []float64{1, 1, 1, 3, 1, 1, 1}
+	broadened, _ := broaden(periods, values, 0.15)
+	expected := []float64{1, 1, 3, 3, 3, 1, 1}
+	for j := range periods {
+		if broadened[j] < expected[j] || (expected[j] == 3 && broadened[j] != 3) {
+			t.Errorf("Expected %v, got %v", expected, broadened)
+			break
+		}
+	}
+	if broadened[0] != 1 || broadened[6] != 1 {
+		t.Errorf("Expected values away from the peak unchanged, got %v", broadened)
+	}
+}
@@ -0,0 +1,281 @@
+package mdof
+
+import (
+	"errors"
+	"math"
+
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+)
+
+// TBDYParameters are the TBDY 2018 parameters of the reduced design spectrum and the base shear scaling.
+type TBDYParameters struct {
+	R                float64 // structural behavior factor
+	D                float64 // overstrength factor
+	ImportanceFactor float64
+	SDS              float64 // short period design spectral acceleration (g)
+	SD1              float64 // 1 s design spectral acceleration (g)
+}
+
+// RSAOptions controls the response spectrum analysis.
+type RSAOptions struct {
+	Combination string  // "srss", "cqc" or "abs"
+	Damping     float64 // damping ratio of the modes in the "cqc" correlation coefficients
+	NumModes    int     // number of modes, 0 for all modes
+	// TBDY, if not nil, reduces the spectrum by Ra(T) and scales the responses so that the base shear is at least
+	// 0.80 times the equivalent lateral force base shear of TBDY 2018.
+	TBDY *TBDYParameters
+}
+
+// RSAResult holds the modal and combined responses of the floors or stories. The modal responses are indexed by mode
+// and floor, and are not multiplied by the scale factor of the combined responses.
+type RSAResult struct {
+	Periods                 []float64
+	SpectralAccelerations   []float64 // design (reduced) spectral accelerations of the modes (g)
+	EffectiveMassRatios     []float64
+	CumulativeMassRatios    []float64
+	ModalDisplacements      [][]float64 // unscaled (cm)
+	ModalDrifts             [][]float64 // unscaled (cm)
+	ModalStoryShears        [][]float64 // unscaled (kN)
+	ModalFloorAccelerations [][]float64 // unscaled (g)
+	ModalBaseShears         []float64   // unscaled (kN)
+	Displacements           []float64   // cm
+	Drifts                  []float64   // cm
+	StoryShears             []float64   // kN
+	FloorAccelerations      []float64   // g
+	BaseShear               float64     // kN
+	EquivalentBaseShear     float64     // TBDY equivalent lateral force base shear VtE (kN)
+	ScaleFactor             float64     // applied to the combined responses only
+}
+
+// DefaultRSAOptions returns the CQC combination of all the modes with 5% damping.
+func DefaultRSAOptions() RSAOptions {
+	return RSAOptions{Combination: "cqc", Damping: 0.05}
+}
+
+// ResponseSpectrumAnalysis returns the responses of the building to the design spectrum. See
+// ModalResponseSpectrumAnalysis.
+func (b *ShearBuilding) ResponseSpectrumAnalysis(periods, spectrum []float64, options RSAOptions) (*RSAResult, error) {
+	return ModalResponseSpectrumAnalysis(b.Masses, b.Modes.Periods, b.Modes.ModeShapes, periods, spectrum, options)
+}
+
+// ModalResponseSpectrumAnalysis returns the responses of floors with the given masses (t) and modes, indexed by
+// mode and floor from the first floor up, to the design spectrum of spectral accelerations (g) at increasing
+// periods, which is interpolated log-log at the modal periods. The mode shapes need not be normalized.
+func ModalResponseSpectrumAnalysis(
+	masses, modalPeriods []float64, modeShapes [][]float64, periods, spectrum []float64, options RSAOptions,
+) (*RSAResult, error) {
+	numFloors := len(masses)
+	if numFloors == 0 || len(modalPeriods) == 0 || len(modeShapes) != len(modalPeriods) {
+		return nil, errors.New("masses and modes must not be empty and each mode must have a shape")
+	}
+	for _, shape := range modeShapes {
+		if len(shape) != numFloors {
+			return nil, errors.New("mode shapes must have a component at each floor")
+		}
+	}
+	numModes := options.NumModes
+	if numModes == 0 {
+		numModes = len(modalPeriods)
+	}
+	if numModes < 0 || numModes > len(modalPeriods) {
+		return nil, errors.New("number of modes must be between 0 and the number of modes")
+	}
+	if options.Damping < 0 || options.Damping >= 1 {
+		return nil, errors.New("damping ratio must be in [0, 1)")
+	}
+	if options.TBDY != nil {
+		if err := options.TBDY.check(); err != nil {
+			return nil, err
+		}
+	}
+	accelerations, err := rs.InterpolateLogLog(periods, spectrum, modalPeriods[:numModes])
+	if err != nil {
+		return nil, err
+	}
+
+	var totalMass float64
+	for _, mass := range masses {
+		totalMass += mass
+	}
+	result := RSAResult{
+		Periods:                 append([]float64{}, modalPeriods[:numModes]...),
+		SpectralAccelerations:   accelerations,
+		EffectiveMassRatios:     make([]float64, numModes),
+		CumulativeMassRatios:    make([]float64, numModes),
+		ModalDisplacements:      make([][]float64, numModes),
+		ModalDrifts:             make([][]float64, numModes),
+		ModalStoryShears:        make([][]float64, numModes),
+		ModalFloorAccelerations: make([][]float64, numModes),
+		ModalBaseShears:         make([]float64, numModes),
+		ScaleFactor:             1,
+	}
+	for mode := 0; mode < numModes; mode++ {
+		period, shape := modalPeriods[mode], modeShapes[mode]
+		if period <= 0 {
+			return nil, errors.New("modal periods must be positive")
+		}
+		if options.TBDY != nil {
+			accelerations[mode] /= options.TBDY.reduction(period)
+		}
+		var participation, modalMass float64
+		for i, mass := range masses {
+			participation += mass * shape[i]
+			modalMass += mass * shape[i] * shape[i]
+		}
+		gamma := participation / modalMass
+		result.EffectiveMassRatios[mode] = participation * participation / modalMass / totalMass
+		result.CumulativeMassRatios[mode] = result.EffectiveMassRatios[mode]
+		if mode > 0 {
+			result.CumulativeMassRatios[mode] += result.CumulativeMassRatios[mode-1]
+		}
+
+		acceleration := accelerations[mode] * gravity
+		omega := 2 * math.Pi / period
+		displacements := make([]float64, numFloors)
+		drifts := make([]float64, numFloors)
+		shears := make([]float64, numFloors)
+		floorAccelerations := make([]float64, numFloors)
+		for i := range masses {
+			displacements[i] = gamma * shape[i] * acceleration / (omega * omega) * 100
+			drifts[i] = displacements[i]
+			if i > 0 {
+				drifts[i] -= displacements[i-1]
+			}
+			floorAccelerations[i] = gamma * shape[i] * accelerations[mode]
+		}
+		for i := numFloors - 1; i >= 0; i-- {
+			shears[i] = masses[i] * gamma * shape[i] * acceleration
+			if i < numFloors-1 {
+				shears[i] += shears[i+1]
+			}
+		}
+		result.ModalDisplacements[mode] = displacements
+		result.ModalDrifts[mode] = drifts
+		result.ModalStoryShears[mode] = shears
+		result.ModalFloorAccelerations[mode] = floorAccelerations
+		result.ModalBaseShears[mode] = shears[0]
+	}
+
+	combine, err := combination(options.Combination, result.Periods, options.Damping)
+	if err != nil {
+		return nil, err
+	}
+	result.Displacements = combineFloors(combine, result.ModalDisplacements)
+	result.Drifts = combineFloors(combine, result.ModalDrifts)
+	result.StoryShears = combineFloors(combine, result.ModalStoryShears)
+	result.FloorAccelerations = combineFloors(combine, result.ModalFloorAccelerations)
+	result.BaseShear = combine(result.ModalBaseShears)
+
+	if options.TBDY != nil {
+		result.EquivalentBaseShear = options.TBDY.equivalentBaseShear(totalMass, modalPeriods[0], periods, spectrum)
+		const gammaE = 0.80
+		if minimum := gammaE * result.EquivalentBaseShear; result.BaseShear < minimum {
+			result.ScaleFactor = minimum / result.BaseShear
+		}
+		for _, responses := range [][]float64{
+			result.Displacements, result.Drifts, result.StoryShears, result.FloorAccelerations,
+		} {
+			for i := range responses {
+				responses[i] *= result.ScaleFactor
+			}
+		}
+		result.BaseShear *= result.ScaleFactor
+	}
+	return &result, nil
+}
+
+// combination returns the function combining the modal responses.
+func combination(method string, periods []float64, damping float64) (func([]float64) float64, error) {
+	switch method {
+	case "srss":
+		return func(responses []float64) float64 {
+			var sum float64
+			for _, response := range responses {
+				sum += response * response
+			}
+			return math.Sqrt(sum)
+		}, nil
+	case "abs":
+		return func(responses []float64) float64 {
+			var sum float64
+			for _, response := range responses {
+				sum += math.Abs(response)
+			}
+			return sum
+		}, nil
+	case "cqc":
+		n := len(periods)
+		rho := make([][]float64, n)
+		for i := range rho {
+			rho[i] = make([]float64, n)
+			for j := range rho[i] {
+				rho[i][j] = correlation(periods[j]/periods[i], damping)
+			}
+		}
+		return func(responses []float64) float64 {
+			var sum float64
+			for i := range responses {
+				for j := range responses {
+					sum += rho[i][j] * responses[i] * responses[j]
+				}
+			}
+			return math.Sqrt(math.Max(sum, 0))
+		}, nil
+	default:
+		return nil, errors.New("modal combination not supported")
+	}
+}
+
+// correlation returns the correlation coefficient of two modes with the frequency ratio and equal damping ratios
+// (Der Kiureghian, 1981).
+func correlation(ratio, damping float64) float64 {
+	if damping == 0 {
+		if ratio == 1 {
+			return 1
+		}
+		return 0
+	}
+	numerator := 8 * damping * damping * (1 + ratio) * math.Pow(ratio, 1.5)
+	denominator := (1-ratio*ratio)*(1-ratio*ratio) + 4*damping*damping*ratio*(1+ratio)*(1+ratio)
+	return numerator / denominator
+}
+
+// combineFloors combines the modal responses, indexed by mode and floor, at each floor.
+func combineFloors(combine func([]float64) float64, modal [][]float64) []float64 {
+	combined := make([]float64, len(modal[0]))
+	responses := make([]float64, len(modal))
+	for floor := range combined {
+		for mode := range modal {
+			responses[mode] = modal[mode][floor]
+		}
+		combined[floor] = combine(responses)
+	}
+	return combined
+}
+
+func (p *TBDYParameters) check() error {
+	if p.R <= 0 || p.D <= 0 || p.ImportanceFactor <= 0 || p.SDS <= 0 || p.SD1 <= 0 {
+		return errors.New("TBDY parameters must be positive")
+	}
+	if p.D > p.R/p.ImportanceFactor {
+		return errors.New("overstrength factor must not exceed R/I")
+	}
+	return nil
+}
+
+// reduction returns the seismic load reduction factor Ra(T) of TBDY 2018, Equation 4.1.
+func (p *TBDYParameters) reduction(period float64) float64 {
+	tb := p.SD1 / p.SDS
+	if period > tb {
+		return p.R / p.ImportanceFactor
+	}
+	return p.D + (p.R/p.ImportanceFactor-p.D)*period/tb
+}
+
+// equivalentBaseShear returns the equivalent lateral force base shear of TBDY 2018, Equation 4.19, of the total
+// mass (t) at the fundamental period, VtE = mt SaR(Tp) g >= 0.04 mt I SDS g.
+func (p *TBDYParameters) equivalentBaseShear(totalMass, period float64, periods, spectrum []float64) float64 {
+	acceleration, _ := rs.InterpolateLogLog(periods, spectrum, []float64{period})
+	baseShear := totalMass * acceleration[0] / p.reduction(period) * gravity
+	return math.Max(baseShear, 0.04*totalMass*p.ImportanceFactor*p.SDS*gravity)
+}
@@ -0,0 +1,124 @@
+package mdof
+
+import (
+	"math"
+	"testing"
+
+	ds "github.com/geoport/GoQuakeLib/design_spectrums"
+)
+
+func TestResponseSpectrumAnalysisSingleStory(t *testing.T) {
+	building, _ := NewShearBuilding([]float64{100}, []float64{100 * math.Pow(2*math.Pi/0.5, 2)}, DefaultDampingOptions())
+	spectrum, periods := ds.GetSpectrumByTBDY(0.01, 4, 1.2, 0.45, true)
+	for _, method := range []string{"srss", "cqc", "abs"} {
+		result, err := building.ResponseSpectrumAnalysis(periods, spectrum, RSAOptions{Combination: method, Damping: 0.05})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expected := 100 * 0.45 / 0.5 * gravity
+		if math.Abs(result.BaseShear-expected) > 1e-9*expected {
+			t.Errorf("Expected base shear %f with %s, got %f", expected, method, result.BaseShear)
+		}
+		displacement := 0.45 / 0.5 * gravity / math.Pow(2*math.Pi/0.5, 2) * 100
+		if math.Abs(result.Displacements[0]-displacement) > 1e-9*displacement {
+			t.Errorf("Expected displacement %f with %s, got %f", displacement, method, result.Displacements[0])
+		}
+	}
+}
+
+func TestResponseSpectrumAnalysis(t *testing.T) {
+	building, _ := NewShearBuilding([]float64{300, 300, 250, 200}, []float64{5e5, 4e5, 3e5, 2e5}, DefaultDampingOptions())
+	spectrum, periods := ds.GetSpectrumByTBDY(0.01, 4, 1.2, 0.45, true)
+	results := map[string]*RSAResult{}
+	for _, method := range []string{"srss", "cqc", "abs"} {
+		result, err := building.ResponseSpectrumAnalysis(periods, spectrum, RSAOptions{Combination: method, Damping: 0.05})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		results[method] = result
+	}
+	srss, cqc, abs := results["srss"], results["cqc"], results["abs"]
+	if math.Abs(srss.CumulativeMassRatios[3]-1) > 1e-12 {
+		t.Errorf("Expected the effective masses of all modes to sum to the total mass")
+	}
+	for floor := range srss.StoryShears {
+		if abs.StoryShears[floor] < srss.StoryShears[floor] {
+			t.Errorf("Expected the absolute sum above SRSS at story %d", floor+1)
+		}
+		// the modes are well separated
+		if math.Abs(cqc.StoryShears[floor]-srss.StoryShears[floor]) > 0.02*srss.StoryShears[floor] {
+			t.Errorf("Expected CQC close to SRSS at story %d, got %f and %f", floor+1, cqc.StoryShears[floor],
+				srss.StoryShears[floor])
+		}
+	}
+	var massRatio float64
+	for mode, ratio := range building.Modes.EffectiveMassRatios {
+		massRatio += ratio
+		if math.Abs(srss.CumulativeMassRatios[mode]-massRatio) > 1e-12 {
+			t.Errorf("Expected cumulative mass ratio %f of mode %d", massRatio, mode+1)
+		}
+	}
+
+	if _, err := building.ResponseSpectrumAnalysis(periods, spectrum, RSAOptions{Combination: "dsc"}); err == nil {
+		t.Errorf("Expected error for an unsupported combination")
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	if math.Abs(correlation(1, 0.05)-1) > 1e-12 {
+		t.Errorf("Expected unit correlation of equal frequencies")
+	}
+	// 8 0.05^2 1.8 0.8^1.5 / ((1 - 0.8^2)^2 + 4 0.05^2 0.8 1.8^2)
+	if rho := correlation(0.8, 0.05); math.Abs(rho-0.165635) > 1e-6 {
+		t.Errorf("Expected correlation 0.165635, got %f", rho)
+	}
+	if math.Abs(correlation(0.5, 0.05)-correlation(2, 0.05)) > 1e-12 {
+		t.Errorf("Expected symmetric correlation")
+	}
+}
+
+func TestTBDYScaling(t *testing.T) {
+	building, _ := NewShearBuilding([]float64{300, 300, 250, 200}, []float64{5e5, 4e5, 3e5, 2e5}, DefaultDampingOptions())
+	spectrum, periods := ds.GetSpectrumByTBDY(0.01, 4, 1.2, 0.45, true)
+	tbdy := TBDYParameters{R: 8, D: 3, ImportanceFactor: 1, SDS: 1.2, SD1: 0.45}
+	options := RSAOptions{Combination: "cqc", Damping: 0.05, TBDY: &tbdy}
+	result, err := building.ResponseSpectrumAnalysis(periods, spectrum, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	fundamental := building.Modes.Periods[0]
+	reduction := 8.
+	if fundamental <= 0.375 {
+		reduction = 3 + 5*fundamental/0.375
+	}
+	unreduced, _ := building.ResponseSpectrumAnalysis(periods, spectrum, DefaultRSAOptions())
+	if math.Abs(result.SpectralAccelerations[0]-unreduced.SpectralAccelerations[0]/reduction) > 1e-12 {
+		t.Errorf("Expected spectrum reduced by Ra = %f", reduction)
+	}
+	if result.BaseShear < 0.8*result.EquivalentBaseShear*(1-1e-12) {
+		t.Errorf("Expected base shear of at least 0.8 VtE = %f, got %f", 0.8*result.EquivalentBaseShear, result.BaseShear)
+	}
+	if result.ScaleFactor > 1 && math.Abs(result.BaseShear-0.8*result.EquivalentBaseShear) > 1e-9 {
+		t.Errorf("Expected scaled base shear equal to 0.8 VtE")
+	}
+	minimum := 0.04 * 1050 * 1.2 * gravity
+	if result.EquivalentBaseShear < minimum {
+		t.Errorf("Expected VtE of at least %f, got %f", minimum, result.EquivalentBaseShear)
+	}
+
+	// the minimum base shear 0.04 mt I SDS g governs the equivalent lateral force of a flexible building
+	flexible, _ := NewShearBuilding([]float64{300, 300, 250, 200}, []float64{3e4, 2e4, 2e4, 1e4}, DefaultDampingOptions())
+	result, _ = flexible.ResponseSpectrumAnalysis(periods, spectrum, options)
+	if math.Abs(result.EquivalentBaseShear-minimum) > 1e-9 || result.ScaleFactor <= 1 {
+		t.Errorf("Expected VtE = %f and a scaled response, got %f and %f", minimum, result.EquivalentBaseShear,
+			result.ScaleFactor)
+	}
+	if math.Abs(result.BaseShear-0.8*minimum) > 1e-9 {
+		t.Errorf("Expected scaled base shear %f, got %f", 0.8*minimum, result.BaseShear)
+	}
+
+	tbdy.D = 10
+	if _, err := building.ResponseSpectrumAnalysis(periods, spectrum, options); err == nil {
+		t.Errorf("Expected error for D above R/I")
+	}
+}
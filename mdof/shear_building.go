@@ -0,0 +1,215 @@
+package mdof
+
+import (
+	"errors"
+	"math"
+)
+
+// DampingOptions defines the viscous damping of a shear building.
+type DampingOptions struct {
+	// Model is "rayleigh" (mass and stiffness proportional) or "modal".
+	Model string
+	// Ratios are the damping ratios of the two anchor modes of "rayleigh", one value applying to both, or of each mode
+	// of "modal", the last value applying to the higher modes.
+	Ratios []float64
+	// RayleighModes are the numbers, starting from 1, of the anchor modes of "rayleigh".
+	RayleighModes [2]int
+}
+
+// ModalProperties holds the modes of a shear building in ascending order of frequency. The mode shapes are mass
+// normalized, indexed by mode and floor, with a positive roof component.
+type ModalProperties struct {
+	Periods              []float64 // s
+	Frequencies          []float64 // circular frequencies (rad/s)
+	ModeShapes           [][]float64
+	ParticipationFactors []float64
+	EffectiveMasses      []float64 // t
+	EffectiveMassRatios  []float64
+	DampingRatios        []float64
+}
+
+// ShearBuilding is a linear shear building with lumped floor masses and story stiffnesses, ordered from the first
+// story up.
+type ShearBuilding struct {
+	Masses      []float64 // t
+	Stiffnesses []float64 // kN/m
+	Damping     DampingOptions
+	Modes       *ModalProperties
+	rayleigh    [2]float64 // mass and stiffness coefficients of "rayleigh"
+}
+
+// DefaultDampingOptions returns Rayleigh damping with 5% damping in the first two modes.
+func DefaultDampingOptions() DampingOptions {
+	return DampingOptions{Model: "rayleigh", Ratios: []float64{0.05}, RayleighModes: [2]int{1, 2}}
+}
+
+// NewShearBuilding returns the shear building with its modal properties. A single story building with Rayleigh
+// damping is damped in proportion to its mass.
+func NewShearBuilding(masses, stiffnesses []float64, damping DampingOptions) (*ShearBuilding, error) {
+	if len(masses) == 0 || len(masses) != len(stiffnesses) {
+		return nil, errors.New("masses and stiffnesses must have the same, non-zero length")
+	}
+	for i := range masses {
+		if masses[i] <= 0 || stiffnesses[i] <= 0 {
+			return nil, errors.New("masses and stiffnesses must be positive")
+		}
+	}
+	if len(damping.Ratios) == 0 {
+		return nil, errors.New("damping ratios are empty")
+	}
+	for _, ratio := range damping.Ratios {
+		if ratio < 0 || ratio >= 1 {
+			return nil, errors.New("damping ratios must be in [0, 1)")
+		}
+	}
+	building := ShearBuilding{
+		Masses:      append([]float64{}, masses...),
+		Stiffnesses: append([]float64{}, stiffnesses...),
+		Damping:     damping,
+	}
+	building.Modes = building.modalProperties()
+	if err := building.setDamping(); err != nil {
+		return nil, err
+	}
+	return &building, nil
+}
+
+// NumFloors returns the number of floors of the building.
+func (b *ShearBuilding) NumFloors() int {
+	return len(b.Masses)
+}
+
+// StiffnessMatrix returns the lateral stiffness matrix of the floors (kN/m).
+func (b *ShearBuilding) StiffnessMatrix() [][]float64 {
+	n := b.NumFloors()
+	k := make([][]float64, n)
+	for i := range k {
+		k[i] = make([]float64, n)
+	}
+	for i, stiffness := range b.Stiffnesses {
+		k[i][i] += stiffness
+		if i > 0 {
+			k[i-1][i-1] += stiffness
+			k[i-1][i] -= stiffness
+			k[i][i-1] -= stiffness
+		}
+	}
+	return k
+}
+
+// DampingMatrix returns the viscous damping matrix of the floors (kN*s/m).
+func (b *ShearBuilding) DampingMatrix() [][]float64 {
+	n := b.NumFloors()
+	c := b.StiffnessMatrix()
+	if b.Damping.Model == "rayleigh" {
+		for i := range c {
+			for j := range c[i] {
+				c[i][j] *= b.rayleigh[1]
+			}
+			c[i][i] += b.rayleigh[0] * b.Masses[i]
+		}
+		return c
+	}
+	for i := range c {
+		for j := range c[i] {
+			c[i][j] = 0
+		}
+	}
+	for mode, shape := range b.Modes.ModeShapes {
+		factor := 2 * b.Modes.DampingRatios[mode] * b.Modes.Frequencies[mode]
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				c[i][j] += factor * b.Masses[i] * shape[i] * b.Masses[j] * shape[j]
+			}
+		}
+	}
+	return c
+}
+
+// modalProperties solves the eigenproblem K phi = omega^2 M phi through the symmetric matrix M^-1/2 K M^-1/2.
+func (b *ShearBuilding) modalProperties() *ModalProperties {
+	n := b.NumFloors()
+	k := b.StiffnessMatrix()
+	for i := range k {
+		for j := range k[i] {
+			k[i][j] /= math.Sqrt(b.Masses[i] * b.Masses[j])
+		}
+	}
+	eigenvalues, eigenvectors := jacobiEigen(k)
+
+	var totalMass float64
+	for _, mass := range b.Masses {
+		totalMass += mass
+	}
+	modes := ModalProperties{
+		Periods:              make([]float64, n),
+		Frequencies:          make([]float64, n),
+		ModeShapes:           make([][]float64, n),
+		ParticipationFactors: make([]float64, n),
+		EffectiveMasses:      make([]float64, n),
+		EffectiveMassRatios:  make([]float64, n),
+		DampingRatios:        make([]float64, n),
+	}
+	for mode, eigenvalue := range eigenvalues {
+		omega := math.Sqrt(eigenvalue)
+		modes.Frequencies[mode] = omega
+		modes.Periods[mode] = 2 * math.Pi / omega
+		shape := make([]float64, n)
+		sign := 1.
+		if eigenvectors[n-1][mode] < 0 {
+			sign = -1
+		}
+		var gamma float64
+		for i := range shape {
+			shape[i] = sign * eigenvectors[i][mode] / math.Sqrt(b.Masses[i])
+			gamma += b.Masses[i] * shape[i]
+		}
+		modes.ModeShapes[mode] = shape
+		modes.ParticipationFactors[mode] = gamma
+		modes.EffectiveMasses[mode] = gamma * gamma
+		modes.EffectiveMassRatios[mode] = gamma * gamma / totalMass
+	}
+	return &modes
+}
+
+// setDamping sets the Rayleigh coefficients and the damping ratio of each mode.
+func (b *ShearBuilding) setDamping() error {
+	modes := b.Modes
+	switch b.Damping.Model {
+	case "rayleigh":
+		if b.NumFloors() == 1 {
+			b.rayleigh = [2]float64{2 * b.Damping.Ratios[0] * modes.Frequencies[0], 0}
+			modes.DampingRatios[0] = b.Damping.Ratios[0]
+			return nil
+		}
+		first, second := b.Damping.RayleighModes[0]-1, b.Damping.RayleighModes[1]-1
+		if first < 0 || second <= first || second >= b.NumFloors() {
+			return errors.New("rayleigh modes must be increasing mode numbers of the building")
+		}
+		ratioI, ratioJ := b.Damping.Ratios[0], b.Damping.Ratios[0]
+		if len(b.Damping.Ratios) > 1 {
+			ratioJ = b.Damping.Ratios[1]
+		}
+		omegaI, omegaJ := modes.Frequencies[first], modes.Frequencies[second]
+		denominator := omegaJ*omegaJ - omegaI*omegaI
+		b.rayleigh = [2]float64{
+			2 * omegaI * omegaJ * (ratioI*omegaJ - ratioJ*omegaI) / denominator,
+			2 * (ratioJ*omegaJ - ratioI*omegaI) / denominator,
+		}
+		for mode, omega := range modes.Frequencies {
+			modes.DampingRatios[mode] = b.rayleigh[0]/(2*omega) + b.rayleigh[1]*omega/2
+		}
+		return nil
+	case "modal":
+		last := len(b.Damping.Ratios) - 1
+		for mode := range modes.DampingRatios {
+			modes.DampingRatios[mode] = b.Damping.Ratios[last]
+			if mode < last {
+				modes.DampingRatios[mode] = b.Damping.Ratios[mode]
+			}
+		}
+		return nil
+	default:
+		return errors.New("damping model not supported")
+	}
+}
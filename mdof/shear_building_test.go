@@ -0,0 +1,64 @@
+package mdof
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewShearBuilding(t *testing.T) {
+	building, err := NewShearBuilding([]float64{1, 1}, []float64{1, 1}, DefaultDampingOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	modes := building.Modes
+	for mode, omega2 := range []float64{(3 - math.Sqrt(5)) / 2, (3 + math.Sqrt(5)) / 2} {
+		if math.Abs(modes.Frequencies[mode]-math.Sqrt(omega2)) > 1e-12 {
+			t.Errorf("Expected frequency %f of mode %d, got %f", math.Sqrt(omega2), mode+1, modes.Frequencies[mode])
+		}
+		if math.Abs(modes.DampingRatios[mode]-0.05) > 1e-12 {
+			t.Errorf("Expected 5%% damping of anchor mode %d, got %f", mode+1, modes.DampingRatios[mode])
+		}
+	}
+	if modes.ModeShapes[0][1] <= 0 || modes.ModeShapes[1][1] <= 0 {
+		t.Errorf("Expected positive roof components, got %v", modes.ModeShapes)
+	}
+	if math.Abs(modes.EffectiveMassRatios[0]+modes.EffectiveMassRatios[1]-1) > 1e-12 {
+		t.Errorf("Expected effective mass ratios summing to 1, got %v", modes.EffectiveMassRatios)
+	}
+
+	for _, damping := range []DampingOptions{
+		DefaultDampingOptions(),
+		{Model: "modal", Ratios: []float64{0.02, 0.05}},
+	} {
+		building, _ := NewShearBuilding([]float64{300, 250, 200}, []float64{4e5, 3e5, 2e5}, damping)
+		c := building.DampingMatrix()
+		for m, shape := range building.Modes.ModeShapes {
+			for n, other := range building.Modes.ModeShapes {
+				var product float64
+				for i := range shape {
+					for j := range other {
+						product += shape[i] * c[i][j] * other[j]
+					}
+				}
+				expected := 0.
+				if m == n {
+					expected = 2 * building.Modes.DampingRatios[m] * building.Modes.Frequencies[m]
+				}
+				if math.Abs(product-expected) > 1e-9 {
+					t.Errorf("Expected classical damping with %s, got %f for modes %d and %d", damping.Model, product, m, n)
+				}
+			}
+		}
+		if damping.Model == "modal" && building.Modes.DampingRatios[2] != 0.05 {
+			t.Errorf("Expected the last modal damping ratio for higher modes")
+		}
+	}
+
+	if _, err := NewShearBuilding([]float64{1}, []float64{1, 2}, DefaultDampingOptions()); err == nil {
+		t.Errorf("Expected error for mismatched masses and stiffnesses")
+	}
+	damping := DampingOptions{Model: "rayleigh", Ratios: []float64{0.05}, RayleighModes: [2]int{1, 3}}
+	if _, err := NewShearBuilding([]float64{1, 1}, []float64{1, 1}, damping); err == nil {
+		t.Errorf("Expected error for an anchor mode beyond the number of floors")
+	}
+}
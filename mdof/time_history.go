@@ -0,0 +1,157 @@
+package mdof
+
+import (
+	"errors"
+
+	"github.com/geoport/GoQuakeLib/internal/newmark"
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+const gravity = 9.81 // m/s2
+
+// AnalysisOptions selects the time-history analysis of a shear building.
+type AnalysisOptions struct {
+	Method   string // "newmark" (direct integration of all floors) or "modal" (modal superposition)
+	NumModes int    // number of modes of "modal", 0 for all modes
+}
+
+// Response holds the time histories of a shear building, indexed by floor or story and time.
+type Response struct {
+	Times              []float64
+	Displacements      [][]float64 // floor displacements relative to the ground (cm)
+	FloorAccelerations [][]float64 // absolute floor accelerations (g)
+	Drifts             [][]float64 // inter-story drifts (cm)
+	StoryShears        [][]float64 // kN
+	BaseShears         []float64   // kN
+}
+
+// DefaultAnalysisOptions returns the direct Newmark integration.
+func DefaultAnalysisOptions() AnalysisOptions {
+	return AnalysisOptions{Method: "newmark"}
+}
+
+// TimeHistory returns the response of the building to the ground accelerations (g) of the motion.
+func (b *ShearBuilding) TimeHistory(motion ts.MotionData, options AnalysisOptions) (*Response, error) {
+	if len(motion.Accelerations) < 2 {
+		return nil, errors.New("at least two acceleration samples are required")
+	}
+	if motion.TimeStep <= 0 {
+		return nil, errors.New("time step must be a positive number")
+	}
+	switch options.Method {
+	case "newmark":
+		return b.newmark(motion.Accelerations, motion.TimeStep)
+	case "modal":
+		if options.NumModes < 0 || options.NumModes > b.NumFloors() {
+			return nil, errors.New("number of modes must be between 0 and the number of floors")
+		}
+		return b.modalSuperposition(motion.Accelerations, motion.TimeStep, options.NumModes)
+	default:
+		return nil, errors.New("analysis method not supported")
+	}
+}
+
+func newResponse(numFloors, numSamples int, dt float64) *Response {
+	response := Response{
+		Times:              make([]float64, numSamples),
+		Displacements:      make([][]float64, numFloors),
+		FloorAccelerations: make([][]float64, numFloors),
+		Drifts:             make([][]float64, numFloors),
+		StoryShears:        make([][]float64, numFloors),
+	}
+	for i := range response.Times {
+		response.Times[i] = float64(i) * dt
+	}
+	for floor := 0; floor < numFloors; floor++ {
+		response.Displacements[floor] = make([]float64, numSamples)
+		response.FloorAccelerations[floor] = make([]float64, numSamples)
+		response.Drifts[floor] = make([]float64, numSamples)
+		response.StoryShears[floor] = make([]float64, numSamples)
+	}
+	return &response
+}
+
+// setStoryForces sets the drifts and the elastic story shears from the displacements.
+func (b *ShearBuilding) setStoryForces(response *Response) {
+	for story, stiffness := range b.Stiffnesses {
+		for i, displacement := range response.Displacements[story] {
+			drift := displacement
+			if story > 0 {
+				drift -= response.Displacements[story-1][i]
+			}
+			response.Drifts[story][i] = drift
+			response.StoryShears[story][i] = stiffness * drift / 100
+		}
+	}
+	response.BaseShears = response.StoryShears[0]
+}
+
+// modalSuperposition combines the responses of the modal oscillators, computed with the Nigam-Jennings recurrence.
+// The floor accelerations include the ground acceleration not carried by the retained modes.
+func (b *ShearBuilding) modalSuperposition(accelerations []float64, dt float64, numModes int) (*Response, error) {
+	if numModes == 0 {
+		numModes = b.NumFloors()
+	}
+	modes := b.Modes
+	response := newResponse(b.NumFloors(), len(accelerations), dt)
+	for floor := range response.Displacements {
+		var ground float64
+		for mode := 0; mode < numModes; mode++ {
+			ground += modes.ParticipationFactors[mode] * modes.ModeShapes[mode][floor]
+		}
+		for i, acc := range accelerations {
+			response.FloorAccelerations[floor][i] = (1 - ground) * acc
+		}
+	}
+	for mode := 0; mode < numModes; mode++ {
+		sdof, err := rs.CalcSDOFResponse(accelerations, dt, modes.Periods[mode], modes.DampingRatios[mode])
+		if err != nil {
+			return nil, err
+		}
+		for floor := range response.Displacements {
+			factor := modes.ParticipationFactors[mode] * modes.ModeShapes[mode][floor]
+			for i := range accelerations {
+				response.Displacements[floor][i] += factor * sdof.Displacements[i]
+				response.FloorAccelerations[floor][i] += factor * sdof.Accelerations[i]
+			}
+		}
+	}
+	b.setStoryForces(response)
+	return response, nil
+}
+
+// newmark integrates the equations of motion of the floors with the average acceleration Newmark method, whose
+// iterations converge in one step on the linear stiffness.
+func (b *ShearBuilding) newmark(accelerations []float64, dt float64) (*Response, error) {
+	n := b.NumFloors()
+	system := newmark.System{
+		Masses:  b.Masses,
+		Damping: b.DampingMatrix(),
+		Resist: func(u []float64) ([]float64, [][]float64) {
+			k := b.StiffnessMatrix()
+			f := make([]float64, n)
+			for i := range f {
+				for j := range u {
+					f[i] += k[i][j] * u[j]
+				}
+			}
+			return f, k
+		},
+	}
+
+	response := newResponse(n, len(accelerations), dt)
+	state := newmark.NewSystemState(n, accelerations[0]*gravity)
+	for step := 1; step < len(accelerations); step++ {
+		ground := accelerations[step] * gravity
+		if err := system.Step(&state, accelerations[step-1]*gravity, ground, dt); err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			response.Displacements[i][step] = state.U[i] * 100
+			response.FloorAccelerations[i][step] = (state.A[i] + ground) / gravity
+		}
+	}
+	b.setStoryForces(response)
+	return response, nil
+}
@@ -0,0 +1,82 @@
+package mdof
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+var testMotion = ts.MotionData{Accelerations: td.TestMotion["Accelerations"].([]float64), TimeStep: 0.005}
+
+func maxAbs(values []float64) float64 {
+	var peak float64
+	for _, value := range values {
+		peak = math.Max(peak, math.Abs(value))
+	}
+	return peak
+}
+
+func TestSingleStory(t *testing.T) {
+	building, _ := NewShearBuilding([]float64{100}, []float64{100 * math.Pow(2*math.Pi/0.5, 2)}, DefaultDampingOptions())
+	sdof, _ := rs.CalcSDOFResponse(testMotion.Accelerations, testMotion.TimeStep, 0.5, 0.05)
+	for _, method := range []string{"newmark", "modal"} {
+		response, err := building.TimeHistory(testMotion, AnalysisOptions{Method: method})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		displacement := maxAbs(response.Displacements[0])
+		if math.Abs(displacement-sdof.PeakDisplacement) > 0.01*sdof.PeakDisplacement {
+			t.Errorf("Expected SDOF displacement %f with %s, got %f", sdof.PeakDisplacement, method, displacement)
+		}
+		acceleration := maxAbs(response.FloorAccelerations[0])
+		if math.Abs(acceleration-sdof.PeakAcceleration) > 0.01*sdof.PeakAcceleration {
+			t.Errorf("Expected SDOF acceleration %f with %s, got %f", sdof.PeakAcceleration, method, acceleration)
+		}
+		shear := maxAbs(response.BaseShears)
+		expected := building.Stiffnesses[0] * sdof.PeakDisplacement / 100
+		if math.Abs(shear-expected) > 0.01*expected {
+			t.Errorf("Expected base shear %f with %s, got %f", expected, method, shear)
+		}
+	}
+}
+
+func TestTimeHistory(t *testing.T) {
+	building, _ := NewShearBuilding(
+		[]float64{300, 300, 250, 200}, []float64{5e5, 4e5, 3e5, 2e5}, DefaultDampingOptions(),
+	)
+	direct, _ := building.TimeHistory(testMotion, DefaultAnalysisOptions())
+	modal, err := building.TimeHistory(testMotion, AnalysisOptions{Method: "modal"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for floor := 0; floor < building.NumFloors(); floor++ {
+		for name, pair := range map[string][2][]float64{
+			"drift":        {direct.Drifts[floor], modal.Drifts[floor]},
+			"acceleration": {direct.FloorAccelerations[floor], modal.FloorAccelerations[floor]},
+			"story shear":  {direct.StoryShears[floor], modal.StoryShears[floor]},
+		} {
+			expected, output := maxAbs(pair[0]), maxAbs(pair[1])
+			if math.Abs(output-expected) > 0.02*expected {
+				t.Errorf("Expected modal %s %f at floor %d, got %f", name, expected, floor+1, output)
+			}
+		}
+	}
+
+	firstMode, _ := building.TimeHistory(testMotion, AnalysisOptions{Method: "modal", NumModes: 1})
+	roof := building.NumFloors() - 1
+	modes := building.Modes
+	sdof, _ := rs.CalcSDOFResponse(testMotion.Accelerations, testMotion.TimeStep, modes.Periods[0], modes.DampingRatios[0])
+	expected := modes.ParticipationFactors[0] * modes.ModeShapes[0][roof] * sdof.PeakDisplacement
+	if output := maxAbs(firstMode.Displacements[roof]); math.Abs(output-expected) > 1e-9*expected {
+		t.Errorf("Expected first mode roof displacement %f, got %f", expected, output)
+	}
+	if _, err := building.TimeHistory(testMotion, AnalysisOptions{Method: "modal", NumModes: 5}); err == nil {
+		t.Errorf("Expected error for more modes than floors")
+	}
+	if _, err := building.TimeHistory(testMotion, AnalysisOptions{Method: "wilson"}); err == nil {
+		t.Errorf("Expected error for an unsupported method")
+	}
+}
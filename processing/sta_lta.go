@@ -0,0 +1,40 @@
+package processing
+
+import (
+	"errors"
+	"math"
+)
+
+// StaLta computes the classic short-term average over long-term average ratio of a signal.
+//
+// The function takes the following parameters:
+//   - signal: A slice of float64 values representing the input signal.
+//   - staLength: The number of samples in the short-term average window.
+//   - ltaLength: The number of samples in the long-term average window (must be larger than staLength).
+//
+// Both averages are computed on the absolute value of the signal over trailing windows ending at each sample.
+// The ratio is zero for the first ltaLength-1 samples, where the long-term window is not yet filled, and for samples
+// where the long-term average is zero.
+func StaLta(signal []float64, staLength, ltaLength int) (error, []float64) {
+	if len(signal) == 0 {
+		return errors.New("Signal vector is empty"), nil
+	}
+	if staLength < 1 || ltaLength <= staLength {
+		return errors.New("STA length must be positive and smaller than LTA length"), nil
+	}
+
+	cumulative := make([]float64, len(signal)+1)
+	for i, value := range signal {
+		cumulative[i+1] = cumulative[i] + math.Abs(value)
+	}
+
+	ratio := make([]float64, len(signal))
+	for i := ltaLength - 1; i < len(signal); i++ {
+		sta := (cumulative[i+1] - cumulative[i+1-staLength]) / float64(staLength)
+		lta := (cumulative[i+1] - cumulative[i+1-ltaLength]) / float64(ltaLength)
+		if lta > 0 {
+			ratio[i] = sta / lta
+		}
+	}
+	return nil, ratio
+}
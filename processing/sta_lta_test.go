@@ -0,0 +1,29 @@
+package processing
+
+import (
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func TestStaLta(t *testing.T) {
+	signal := np.Ones(100)
+	for i := 80; i < 85; i++ {
+		signal[i] = 11
+	}
+	err, ratio := StaLta(signal, 5, 50)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ratio[48] != 0 || ratio[49] != 1 {
+		t.Errorf("Expected ratio to start at the first full LTA window, got %f and %f", ratio[48], ratio[49])
+	}
+	// STA = 11, LTA = (45 + 55) / 50 = 2
+	if np.Round(ratio[84], 5) != 5.5 {
+		t.Errorf("Expected ratio 5.5 at the end of the transient, got %f", ratio[84])
+	}
+
+	if err, _ = StaLta(signal, 50, 5); err == nil {
+		t.Errorf("Expected error for STA longer than LTA")
+	}
+}
@@ -0,0 +1,321 @@
+package qc
+
+import (
+	"fmt"
+	"math"
+
+	gmp "github.com/geoport/GoQuakeLib/ground_motion_parameters"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+// CheckClipping flags runs of consecutive samples stuck at the peak amplitude (flat-topped peaks).
+func CheckClipping(motion ts.MotionData, thresholds Thresholds) []Flag {
+	if checkMotion(motion) != nil {
+		return nil
+	}
+	var flags []Flag
+	accelerations := motion.Accelerations
+	times := getTimes(motion)
+	pga := np.Max(np.Abs(accelerations))
+	limit := pga * (1 - thresholds.ClippingTolerance)
+
+	closeRun := func(runStart, runEnd int) {
+		runLength := runEnd - runStart
+		if runLength >= thresholds.ClippingMinSamples {
+			flags = append(
+				flags, Flag{
+					Check:    "clipping",
+					Severity: SeverityCritical,
+					Index:    runStart,
+					Time:     times[runStart],
+					Duration: float64(runLength) * motion.TimeStep,
+					Value:    accelerations[runStart],
+					Message:  fmt.Sprintf("%d consecutive samples at the peak amplitude", runLength),
+				},
+			)
+		}
+	}
+
+	runStart := -1
+	for i, acc := range accelerations {
+		sameSign := runStart >= 0 && math.Signbit(acc) == math.Signbit(accelerations[runStart])
+		if runStart >= 0 && (math.Abs(acc) < limit || !sameSign) {
+			closeRun(runStart, i)
+			runStart = -1
+		}
+		if runStart < 0 && math.Abs(acc) >= limit {
+			runStart = i
+		}
+	}
+	if runStart >= 0 {
+		closeRun(runStart, len(accelerations))
+	}
+	return flags
+}
+
+// CheckSpikes flags isolated samples that are much larger than their neighbours.
+func CheckSpikes(motion ts.MotionData, thresholds Thresholds) []Flag {
+	if checkMotion(motion) != nil {
+		return nil
+	}
+	var flags []Flag
+	accelerations := motion.Accelerations
+	times := getTimes(motion)
+	pga := np.Max(np.Abs(accelerations))
+
+	for i := 2; i < len(accelerations)-2; i++ {
+		amplitude := math.Abs(accelerations[i])
+		if amplitude < thresholds.SpikeMinAmplitude*pga {
+			continue
+		}
+		neighbour := math.Max(
+			math.Max(math.Abs(accelerations[i-2]), math.Abs(accelerations[i-1])),
+			math.Max(math.Abs(accelerations[i+1]), math.Abs(accelerations[i+2])),
+		)
+		if amplitude > thresholds.SpikeRatio*neighbour {
+			severity := SeverityWarning
+			if amplitude == pga {
+				severity = SeverityCritical
+			}
+			flags = append(
+				flags, Flag{
+					Check:    "spike",
+					Severity: severity,
+					Index:    i,
+					Time:     times[i],
+					Value:    accelerations[i],
+					Message:  fmt.Sprintf("sample is %.1f times larger than its neighbours", amplitude/neighbour),
+				},
+			)
+		}
+	}
+	return flags
+}
+
+// CheckLateTrigger flags records whose pre-event portion is missing or too short.
+func CheckLateTrigger(motion ts.MotionData, thresholds Thresholds) []Flag {
+	if checkMotion(motion) != nil {
+		return nil
+	}
+	accelerations := motion.Accelerations
+	times := getTimes(motion)
+	pga := np.Max(np.Abs(accelerations))
+	indexes, _ := np.Where(np.Abs(accelerations), func(x float64) bool { return x >= thresholds.PreEventFraction*pga })
+	triggerIndex := indexes[0]
+	preEventDuration := float64(triggerIndex) * motion.TimeStep
+
+	if triggerIndex == 0 {
+		return []Flag{
+			{
+				Check:    "late_trigger",
+				Severity: SeverityCritical,
+				Index:    0,
+				Time:     times[0],
+				Value:    math.Abs(accelerations[0]) / pga,
+				Message:  "record starts during strong shaking, no pre-event portion",
+			},
+		}
+	}
+	if preEventDuration < thresholds.MinPreEventDuration {
+		return []Flag{
+			{
+				Check:    "late_trigger",
+				Severity: SeverityWarning,
+				Index:    triggerIndex,
+				Time:     times[triggerIndex],
+				Duration: preEventDuration,
+				Value:    preEventDuration,
+				Message:  fmt.Sprintf("pre-event portion is only %.2f s long", preEventDuration),
+			},
+		}
+	}
+	return nil
+}
+
+// CheckTruncation flags records whose Arias intensity is still growing at the end of the record.
+func CheckTruncation(motion ts.MotionData, thresholds Thresholds) []Flag {
+	if checkMotion(motion) != nil {
+		return nil
+	}
+	var flags []Flag
+	accelerations := motion.Accelerations
+	times := getTimes(motion)
+	pga := np.Max(np.Abs(accelerations))
+
+	gmpData := gmp.GMPData{}
+	gmpData.CalcAriasIntensity(motion)
+	Ia := gmpData.AriasIntensityArray
+	codaSamples := int(math.Round(thresholds.CodaWindow / motion.TimeStep))
+	if codaSamples >= len(Ia) {
+		codaSamples = len(Ia) - 1
+	}
+	codaIndex := len(Ia) - 1 - codaSamples
+	codaFraction := (Ia[len(Ia)-1] - Ia[codaIndex]) / Ia[len(Ia)-1]
+
+	if codaFraction > thresholds.TruncationAriasFraction {
+		flags = append(
+			flags, Flag{
+				Check:    "truncation",
+				Severity: SeverityWarning,
+				Index:    codaIndex,
+				Time:     times[codaIndex],
+				Duration: float64(codaSamples) * motion.TimeStep,
+				Value:    codaFraction,
+				Message: fmt.Sprintf(
+					"%.1f%% of the Arias intensity accumulates in the last %.1f s", codaFraction*100,
+					thresholds.CodaWindow,
+				),
+			},
+		)
+	}
+
+	lastIndex := len(accelerations) - 1
+	endRatio := math.Abs(accelerations[lastIndex]) / pga
+	if endRatio > thresholds.TruncationEndFraction {
+		flags = append(
+			flags, Flag{
+				Check:    "truncation",
+				Severity: SeverityCritical,
+				Index:    lastIndex,
+				Time:     times[lastIndex],
+				Value:    endRatio,
+				Message:  fmt.Sprintf("record ends at %.0f%% of PGA", endRatio*100),
+			},
+		)
+	}
+	return flags
+}
+
+// movingRMS returns the centered moving root-mean-square of the signal over windowSamples samples.
+func movingRMS(signal []float64, windowSamples int) []float64 {
+	squares := np.Cumsum(np.Pow(signal, 2))
+	half := windowSamples / 2
+	rms := make([]float64, len(signal))
+	for i := range signal {
+		start := i - half - 1
+		end := i + half
+		if end > len(signal)-1 {
+			end = len(signal) - 1
+		}
+		total := squares[end]
+		count := end + 1
+		if start >= 0 {
+			total -= squares[start]
+			count = end - start
+		}
+		rms[i] = math.Sqrt(math.Max(total, 0) / float64(count))
+	}
+	return rms
+}
+
+// CheckMultipleEvents flags records containing more than one distinct burst of strong shaking.
+func CheckMultipleEvents(motion ts.MotionData, thresholds Thresholds) []Flag {
+	if checkMotion(motion) != nil {
+		return nil
+	}
+	var flags []Flag
+	times := getTimes(motion)
+	windowSamples := int(math.Max(math.Round(thresholds.EnvelopeWindow/motion.TimeStep), 1))
+	envelope := movingRMS(motion.Accelerations, windowSamples)
+	maxEnvelope := np.Max(envelope)
+	quietSamples := int(math.Round(thresholds.MinEventSeparation / motion.TimeStep))
+
+	var eventStarts []int
+	var eventPeaks []float64
+	inEvent := false
+	quietCount := quietSamples
+	for i, value := range envelope {
+		if value < thresholds.QuietFraction*maxEnvelope {
+			quietCount++
+			if quietCount >= quietSamples {
+				inEvent = false
+			}
+			continue
+		}
+		quietCount = 0
+		if !inEvent {
+			inEvent = true
+			eventStarts = append(eventStarts, i)
+			eventPeaks = append(eventPeaks, 0)
+		}
+		eventPeaks[len(eventPeaks)-1] = math.Max(eventPeaks[len(eventPeaks)-1], value)
+	}
+
+	eventCount := 0
+	for i, start := range eventStarts {
+		if eventPeaks[i] < thresholds.EventFraction*maxEnvelope {
+			continue
+		}
+		eventCount++
+		if eventCount > 1 {
+			flags = append(
+				flags, Flag{
+					Check:    "multiple_events",
+					Severity: SeverityWarning,
+					Index:    start,
+					Time:     times[start],
+					Value:    eventPeaks[i] / maxEnvelope,
+					Message: fmt.Sprintf(
+						"event %d starts at %.2f s with %.0f%% of the main event envelope", eventCount,
+						times[start], eventPeaks[i]/maxEnvelope*100,
+					),
+				},
+			)
+		}
+	}
+	return flags
+}
+
+// CheckDrift flags long-period drift, i.e. a non-zero final velocity or a displacement that keeps growing.
+func CheckDrift(motion ts.MotionData, thresholds Thresholds) []Flag {
+	if checkMotion(motion) != nil {
+		return nil
+	}
+	var flags []Flag
+	times := getTimes(motion)
+	velocities := motion.Velocities
+	if len(velocities) != len(motion.Accelerations) {
+		velocities = np.Cumtrapz(motion.Accelerations, motion.TimeStep, 0)
+	}
+	displacements := motion.Displacements
+	if len(displacements) != len(motion.Accelerations) {
+		displacements = np.Cumtrapz(velocities, motion.TimeStep, 0)
+	}
+	lastIndex := len(velocities) - 1
+
+	pgv := np.Max(np.Abs(velocities))
+	if pgv > 0 {
+		velocityRatio := math.Abs(velocities[lastIndex]) / pgv
+		if velocityRatio > thresholds.DriftVelocityRatio {
+			flags = append(
+				flags, Flag{
+					Check:    "drift",
+					Severity: SeverityWarning,
+					Index:    lastIndex,
+					Time:     times[lastIndex],
+					Value:    velocityRatio,
+					Message:  fmt.Sprintf("final velocity is %.0f%% of PGV", velocityRatio*100),
+				},
+			)
+		}
+	}
+
+	pgd := np.Max(np.Abs(displacements))
+	if pgd > 0 {
+		displacementRatio := math.Abs(displacements[lastIndex]) / pgd
+		if displacementRatio > thresholds.DriftDisplacementRatio {
+			flags = append(
+				flags, Flag{
+					Check:    "drift",
+					Severity: SeverityWarning,
+					Index:    lastIndex,
+					Time:     times[lastIndex],
+					Value:    displacementRatio,
+					Message:  fmt.Sprintf("final displacement is %.0f%% of PGD", displacementRatio*100),
+				},
+			)
+		}
+	}
+	return flags
+}
@@ -0,0 +1,88 @@
+package qc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCheckClipping(t *testing.T) {
+	motion := copyMotion(testMotion)
+	limit := 0.1
+	for i, acc := range motion.Accelerations {
+		motion.Accelerations[i] = math.Max(math.Min(acc, limit), -limit)
+	}
+	flags := CheckClipping(motion, DefaultThresholds())
+	if len(flags) == 0 {
+		t.Fatalf("Expected clipping flags, got none")
+	}
+	if math.Abs(flags[0].Value) != limit {
+		t.Errorf("Expected clipped value %f, got %f", limit, flags[0].Value)
+	}
+	if len(CheckClipping(testMotion, DefaultThresholds())) != 0 {
+		t.Errorf("Expected no clipping in the original record")
+	}
+}
+
+func TestCheckSpikes(t *testing.T) {
+	motion := copyMotion(testMotion)
+	motion.Accelerations[4000] = 0.5
+	flags := CheckSpikes(motion, DefaultThresholds())
+	if len(flags) != 1 || flags[0].Index != 4000 || flags[0].Severity != SeverityCritical {
+		t.Errorf("Expected one critical spike at index 4000, got %v", flags)
+	}
+}
+
+func TestCheckLateTrigger(t *testing.T) {
+	motion := copyMotion(testMotion)
+	pga := 0.16076
+	triggerIndex := 125
+	motion.Accelerations = motion.Accelerations[triggerIndex-5:]
+	motion.Times = motion.Times[:len(motion.Accelerations)]
+	flags := CheckLateTrigger(motion, DefaultThresholds())
+	if len(flags) != 1 || flags[0].Severity != SeverityWarning {
+		t.Errorf("Expected late trigger warning, got %v", flags)
+	}
+
+	motion.Accelerations = motion.Accelerations[1:]
+	motion.Accelerations[0] = pga
+	motion.Times = motion.Times[:len(motion.Accelerations)]
+	flags = CheckLateTrigger(motion, DefaultThresholds())
+	if len(flags) != 1 || flags[0].Severity != SeverityCritical {
+		t.Errorf("Expected critical late trigger, got %v", flags)
+	}
+}
+
+func TestCheckTruncation(t *testing.T) {
+	motion := copyMotion(testMotion)
+	motion.Accelerations = motion.Accelerations[:400]
+	motion.Times = motion.Times[:400]
+	flags := CheckTruncation(motion, DefaultThresholds())
+	if len(flags) == 0 || flags[0].Check != "truncation" {
+		t.Errorf("Expected truncation flag, got %v", flags)
+	}
+	if len(CheckTruncation(testMotion, DefaultThresholds())) != 0 {
+		t.Errorf("Expected no truncation in the original record")
+	}
+}
+
+func TestCheckMultipleEvents(t *testing.T) {
+	motion := copyMotion(testMotion)
+	n := len(motion.Accelerations)
+	motion.Accelerations = append(motion.Accelerations, motion.Accelerations...)
+	motion.Times = nil
+	flags := CheckMultipleEvents(motion, DefaultThresholds())
+	if len(flags) != 1 || flags[0].Index < n {
+		t.Errorf("Expected second event after index %d, got %v", n, flags)
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	motion := copyMotion(testMotion)
+	for i := range motion.Accelerations {
+		motion.Accelerations[i] += 0.001
+	}
+	flags := CheckDrift(motion, DefaultThresholds())
+	if len(flags) == 0 || flags[0].Check != "drift" {
+		t.Errorf("Expected drift flag, got %v", flags)
+	}
+}
@@ -0,0 +1,142 @@
+package qc
+
+import (
+	"errors"
+
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Flag describes a single quality-control problem found in a record.
+type Flag struct {
+	Check    string  // name of the check that raised the flag, e.g. "clipping"
+	Severity string  // SeverityInfo, SeverityWarning or SeverityCritical
+	Index    int     // sample index where the problem starts
+	Time     float64 // time (s) where the problem starts
+	Duration float64 // duration (s) of the problem, zero for point-like problems
+	Value    float64 // value of the metric that triggered the check
+	Message  string
+}
+
+// Report collects the flags raised by CheckRecord.
+type Report struct {
+	Flags []Flag
+}
+
+// Thresholds holds the tuning parameters of the quality-control checks.
+type Thresholds struct {
+	ClippingTolerance       float64 // samples within this fraction of the peak are treated as "at the peak"
+	ClippingMinSamples      int     // minimum number of consecutive samples at the peak to flag clipping
+	SpikeRatio              float64 // ratio of a sample to its neighbours that marks it as a spike
+	SpikeMinAmplitude       float64 // spikes smaller than this fraction of PGA are ignored
+	PreEventFraction        float64 // fraction of PGA that defines the trigger of strong shaking
+	MinPreEventDuration     float64 // minimum duration (s) of the pre-event portion
+	CodaWindow              float64 // length (s) of the window at the end of the record used for truncation
+	TruncationAriasFraction float64 // Arias intensity fraction allowed to accumulate within CodaWindow
+	TruncationEndFraction   float64 // final amplitude as a fraction of PGA that marks a cut record
+	EnvelopeWindow          float64 // length (s) of the moving RMS window used for event detection
+	EventFraction           float64 // envelope peaks above this fraction of its maximum count as events
+	QuietFraction           float64 // envelope below this fraction of its maximum separates events
+	MinEventSeparation      float64 // minimum quiet duration (s) between two events
+	DriftVelocityRatio      float64 // final velocity as a fraction of PGV that marks drift
+	DriftDisplacementRatio  float64 // final displacement as a fraction of PGD that marks drift
+}
+
+// DefaultThresholds returns the thresholds used when no custom values are required.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		ClippingTolerance:       0.001,
+		ClippingMinSamples:      3,
+		SpikeRatio:              4,
+		SpikeMinAmplitude:       0.2,
+		PreEventFraction:        0.05,
+		MinPreEventDuration:     1,
+		CodaWindow:              2,
+		TruncationAriasFraction: 0.01,
+		TruncationEndFraction:   0.1,
+		EnvelopeWindow:          1,
+		EventFraction:           0.3,
+		QuietFraction:           0.1,
+		MinEventSeparation:      3,
+		DriftVelocityRatio:      0.1,
+		DriftDisplacementRatio:  0.9,
+	}
+}
+
+// HasCritical returns true if the report contains at least one critical flag.
+func (r *Report) HasCritical() bool {
+	for _, flag := range r.Flags {
+		if flag.Severity == SeverityCritical {
+			return true
+		}
+	}
+	return false
+}
+
+// Passed returns true if the record can be used without manual review, i.e. it has no warning or critical flags.
+func (r *Report) Passed() bool {
+	for _, flag := range r.Flags {
+		if flag.Severity != SeverityInfo {
+			return false
+		}
+	}
+	return true
+}
+
+// FlagsOf returns the flags raised by the given check.
+func (r *Report) FlagsOf(check string) []Flag {
+	var flags []Flag
+	for _, flag := range r.Flags {
+		if flag.Check == check {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+func checkMotion(motion ts.MotionData) error {
+	if len(motion.Accelerations) == 0 {
+		return errors.New("no acceleration data")
+	}
+	if motion.TimeStep <= 0 {
+		return errors.New("time step must be a positive number")
+	}
+	if np.Max(np.Abs(motion.Accelerations)) == 0 {
+		return errors.New("acceleration record is all zeros")
+	}
+	return nil
+}
+
+func getTimes(motion ts.MotionData) []float64 {
+	if len(motion.Times) == len(motion.Accelerations) {
+		return motion.Times
+	}
+	times := make([]float64, len(motion.Accelerations))
+	for i := range times {
+		times[i] = float64(i) * motion.TimeStep
+	}
+	return times
+}
+
+// CheckRecord runs all quality-control checks on the motion and returns the flags raised.
+func CheckRecord(motion ts.MotionData, thresholds Thresholds) (*Report, error) {
+	if err := checkMotion(motion); err != nil {
+		return nil, err
+	}
+
+	report := Report{}
+	report.Flags = append(report.Flags, CheckClipping(motion, thresholds)...)
+	report.Flags = append(report.Flags, CheckSpikes(motion, thresholds)...)
+	report.Flags = append(report.Flags, CheckLateTrigger(motion, thresholds)...)
+	report.Flags = append(report.Flags, CheckTruncation(motion, thresholds)...)
+	report.Flags = append(report.Flags, CheckMultipleEvents(motion, thresholds)...)
+	report.Flags = append(report.Flags, CheckDrift(motion, thresholds)...)
+
+	return &report, nil
+}
@@ -0,0 +1,41 @@
+package qc
+
+import (
+	td "github.com/geoport/GoQuakeLib/TestData"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	"testing"
+)
+
+var testMotion = ts.MotionData{
+	Accelerations: td.TestMotion["Accelerations"].([]float64),
+	TimeStep:      td.TestMotion["TimeStep"].(float64),
+	AccUnit:       td.TestMotion["AccUnit"].(string),
+	Times:         td.TestMotion["Times"].([]float64),
+}
+
+func copyMotion(motion ts.MotionData) ts.MotionData {
+	motion.Accelerations = append([]float64{}, motion.Accelerations...)
+	return motion
+}
+
+func TestCheckRecord(t *testing.T) {
+	report, err := CheckRecord(testMotion, DefaultThresholds())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !report.Passed() || len(report.Flags) != 0 {
+		t.Errorf("Expected clean record to pass, got %v", report.Flags)
+	}
+
+	motion := copyMotion(testMotion)
+	motion.Accelerations[len(motion.Accelerations)-1] = 0.1
+	report, _ = CheckRecord(motion, DefaultThresholds())
+	if !report.HasCritical() || len(report.FlagsOf("truncation")) == 0 {
+		t.Errorf("Expected critical truncation flag, got %v", report.Flags)
+	}
+
+	_, err = CheckRecord(ts.MotionData{TimeStep: 0.01}, DefaultThresholds())
+	if err == nil {
+		t.Errorf("Expected error for empty record")
+	}
+}
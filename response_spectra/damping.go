@@ -0,0 +1,117 @@
+package response_spectra
+
+import (
+	"errors"
+	"math"
+)
+
+func checkDamping(damping float64) error {
+	if damping < 0 || damping >= 1 {
+		return errors.New("damping ratio must be in [0, 1)")
+	}
+	return nil
+}
+
+// EC8DampingFactor returns the damping correction factor of Eurocode 8 (EN 1998-1, Eq. 3.6),
+// sqrt(10 / (5 + xi)) >= 0.55 with the damping ratio xi in percent. Undamped spectra have the factor sqrt(2).
+func EC8DampingFactor(damping float64) (float64, error) {
+	if err := checkDamping(damping); err != nil {
+		return 0, err
+	}
+	return math.Max(math.Sqrt(10/(5+100*damping)), 0.55), nil
+}
+
+// TBDYIsolationDampingFactor returns the damping scaling factor of TBDY 2018 for seismically isolated buildings,
+// (10 / (5 + xi))^0.3 with the damping ratio xi in percent. Undamped spectra have the factor 2^0.3.
+func TBDYIsolationDampingFactor(damping float64) (float64, error) {
+	if err := checkDamping(damping); err != nil {
+		return 0, err
+	}
+	return math.Pow(10/(5+100*damping), 0.3), nil
+}
+
+// RezaeianPredictors are the predictors of the damping scaling factor model of Rezaeian et al. (2014).
+type RezaeianPredictors struct {
+	Duration  float64 // significant duration D5-75 (s)
+	Magnitude float64 // moment magnitude
+	Distance  float64 // closest distance to the rupture (km)
+}
+
+// RezaeianTable holds the period dependent coefficients of the damping scaling factor model of Rezaeian et al.
+// (2014). The coefficients of each period are indexed by the term, in the order of the constant, ln(D5-75), M and
+// ln(R+1), and by the power of ln(xi), with the damping ratio xi in percent:
+//
+//	ln(DSF) = sum_p (b_p0 + b_p1 ln(xi) + b_p2 ln(xi)^2) x_p, with x = (1, ln(D5-75), M, ln(R+1)).
+//
+// The model of the paper in terms of duration and magnitude has zero ln(R+1) coefficients, and the one in terms of
+// magnitude and distance has zero ln(D5-75) coefficients. The package does not ship the coefficients of the paper;
+// they must be transcribed from its coefficient tables by the caller.
+type RezaeianTable struct {
+	Periods      []float64 // increasing periods (s)
+	Coefficients [][4][3]float64
+}
+
+// DampingFactor returns the damping scaling factor of the 5% damped spectral acceleration at the period, with the
+// coefficients interpolated linearly in ln(T) between the periods of the table. The damping ratio must be within
+// 0.5% and 30%, the range of the paper.
+func (t RezaeianTable) DampingFactor(damping, period float64, predictors RezaeianPredictors) (float64, error) {
+	if damping < 0.005 || damping > 0.3 {
+		return 0, errors.New("damping ratio must be in [0.005, 0.3]")
+	}
+	n := len(t.Periods)
+	if n == 0 || len(t.Coefficients) != n {
+		return 0, errors.New("table must have coefficients at each period")
+	}
+	if period < t.Periods[0] || period > t.Periods[n-1] {
+		return 0, errors.New("period is outside the table")
+	}
+	if predictors.Duration <= 0 || predictors.Distance < 0 {
+		return 0, errors.New("duration must be positive and distance must not be negative")
+	}
+	upper := 0
+	for upper < n-1 && t.Periods[upper] < period {
+		upper++
+	}
+	lower, weight := upper, 0.
+	if upper > 0 && t.Periods[upper] != period {
+		lower = upper - 1
+		weight = math.Log(period/t.Periods[lower]) / math.Log(t.Periods[upper]/t.Periods[lower])
+	}
+	logDamping := math.Log(100 * damping)
+	x := [4]float64{1, math.Log(predictors.Duration), predictors.Magnitude, math.Log(predictors.Distance + 1)}
+	var logFactor float64
+	for p := range x {
+		for power := 0; power < 3; power++ {
+			b := (1-weight)*t.Coefficients[lower][p][power] + weight*t.Coefficients[upper][p][power]
+			logFactor += b * math.Pow(logDamping, float64(power)) * x[p]
+		}
+	}
+	return math.Exp(logFactor), nil
+}
+
+// ScaleDamping returns the spectra multiplied by the damping scaling factors, either a single factor for all
+// periods or one factor per period, e.g. to convert a 5% damped spectrum to another damping ratio.
+func (rsd *ResponseSpectraData) ScaleDamping(factors []float64) (*ResponseSpectraData, error) {
+	if len(factors) != 1 && len(factors) != len(rsd.Periods) {
+		return nil, errors.New("factors must have a single value or one value per period")
+	}
+	scale := func(values []float64) []float64 {
+		scaled := make([]float64, len(values))
+		for j, value := range values {
+			factor := factors[0]
+			if len(factors) > 1 {
+				factor = factors[j]
+			}
+			scaled[j] = value * factor
+		}
+		return scaled
+	}
+	return &ResponseSpectraData{
+		SpectralAccelerations: scale(rsd.SpectralAccelerations),
+		SpectralVelocities:    scale(rsd.SpectralVelocities),
+		SpectralDisplacements: scale(rsd.SpectralDisplacements),
+		PseudoAccelerations:   scale(rsd.PseudoAccelerations),
+		PseudoVelocities:      scale(rsd.PseudoVelocities),
+		Periods:               append([]float64{}, rsd.Periods...),
+	}, nil
+}
@@ -0,0 +1,112 @@
+package response_spectra
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func TestCalcMultiDampingSpectra(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := np.Arange(0.1, 4, 0.1)
+	dampings := []float64{0.02, 0.05, 0.1, 0.2}
+	spectra, err := CalcMultiDampingSpectra(testAcceleration, 0.005, periods, dampings, DefaultSpectraOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for d, damping := range dampings {
+		expected := ResponseSpectra(testAcceleration, 0.005, append([]float64{}, periods...), damping)
+		if !np.AllClose(spectra[d].SpectralAccelerations, expected.SpectralAccelerations, 1e-12) {
+			t.Errorf("Expected spectra of %.2f damping equal to ResponseSpectra", damping)
+		}
+	}
+	for j := range periods {
+		if spectra[0].SpectralDisplacements[j] < spectra[3].SpectralDisplacements[j] {
+			t.Errorf("Expected displacements to decrease with damping at %.1f s", periods[j])
+		}
+	}
+
+	if _, err = CalcMultiDampingSpectra(testAcceleration, 0.005, periods, []float64{0.05, 1.2}, DefaultSpectraOptions()); err == nil {
+		t.Errorf("Expected error for damping above critical")
+	}
+	if _, err = CalcMultiDampingSpectra(testAcceleration, 0.005, periods, nil, DefaultSpectraOptions()); err == nil {
+		t.Errorf("Expected error for empty damping ratios")
+	}
+}
+
+func TestDampingFactors(t *testing.T) {
+	for _, test := range []struct {
+		damping, ec8, tbdy float64
+	}{
+		{0.05, 1, 1},
+		{0.10, 0.8165, 0.8855},
+		{0.30, 0.55, 0.6867},
+		{0.50, 0.55, 0.5996},
+	} {
+		ec8, _ := EC8DampingFactor(test.damping)
+		tbdy, _ := TBDYIsolationDampingFactor(test.damping)
+		if np.Round(ec8, 4) != test.ec8 || np.Round(tbdy, 4) != test.tbdy {
+			t.Errorf("Expected factors %f and %f at %.2f damping, got %f and %f", test.ec8, test.tbdy, test.damping, ec8, tbdy)
+		}
+	}
+	if _, err := EC8DampingFactor(1); err == nil {
+		t.Errorf("Expected error for critical damping")
+	}
+
+	if ec8, _ := EC8DampingFactor(0); math.Abs(ec8-math.Sqrt2) > 1e-12 {
+		t.Errorf("Expected factor sqrt(2) of undamped spectra, got %f", ec8)
+	}
+}
+
+func TestRezaeianDampingFactor(t *testing.T) {
+	// synthetic coefficients that check the evaluation and interpolation of the model, not those of the paper
+	table := RezaeianTable{
+		Periods: []float64{0.1, 1},
+		Coefficients: [][4][3]float64{
+			{{0, -0.5, 0}, {0.1, 0, 0}, {0, 0, 0}, {0, 0, 0}},
+			{{0, -0.3, 0}, {0, 0, 0}, {0, 0, 0.01}, {0, 0.02, 0}},
+		},
+	}
+	predictors := RezaeianPredictors{Duration: math.E, Magnitude: 7, Distance: math.E - 1}
+	logDamping := math.Log(10)
+	for _, test := range []struct{ period, expected float64 }{
+		{0.1, -0.5*logDamping + 0.1},
+		{1, -0.3*logDamping + 0.07*logDamping*logDamping + 0.02*logDamping},
+		// halfway in ln(T)
+		{math.Sqrt(0.1), -0.4*logDamping + 0.05 + 0.035*logDamping*logDamping + 0.01*logDamping},
+	} {
+		factor, err := table.DampingFactor(0.1, test.period, predictors)
+		if err != nil || math.Abs(math.Log(factor)-test.expected) > 1e-12 {
+			t.Errorf("Expected ln(DSF) %f at %.3f s, got %f (%v)", test.expected, test.period, math.Log(factor), err)
+		}
+	}
+	for _, damping := range []float64{0, 0.4} {
+		if _, err := table.DampingFactor(damping, 0.5, predictors); err == nil {
+			t.Errorf("Expected error for damping ratio %.2f outside the range of the model", damping)
+		}
+	}
+	if _, err := table.DampingFactor(0.1, 2, predictors); err == nil {
+		t.Errorf("Expected error for a period outside the table")
+	}
+}
+
+func TestScaleDamping(t *testing.T) {
+	spectra := &ResponseSpectraData{
+		SpectralAccelerations: []float64{1, 2}, SpectralVelocities: []float64{1, 2}, SpectralDisplacements: []float64{1, 2},
+		PseudoAccelerations: []float64{1, 2}, PseudoVelocities: []float64{1, 2}, Periods: []float64{0.5, 1},
+	}
+	scaled, err := spectra.ScaleDamping([]float64{0.5, 2})
+	if err != nil || scaled.SpectralAccelerations[0] != 0.5 || scaled.PseudoVelocities[1] != 4 {
+		t.Errorf("Expected per-period scaling, got %v", scaled.SpectralAccelerations)
+	}
+	factor, _ := EC8DampingFactor(0.1)
+	scaled, _ = spectra.ScaleDamping([]float64{factor})
+	if scaled.SpectralDisplacements[1] != 2*factor {
+		t.Errorf("Expected uniform scaling")
+	}
+	if _, err = spectra.ScaleDamping([]float64{1, 2, 3}); err == nil {
+		t.Errorf("Expected error for mismatched factors")
+	}
+}
@@ -0,0 +1,173 @@
+package response_spectra
+
+import (
+	"errors"
+	"math"
+
+	"github.com/geoport/GoQuakeLib/internal/numeric"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// EnergyResponse holds the energy time histories per unit mass (cm2/s2) of an oscillator, sampled at the time steps
+// of the motion.
+type EnergyResponse struct {
+	Times                 []float64
+	InputEnergy           []float64 // relative input energy, -integral of the ground acceleration times du
+	AbsoluteInputEnergy   []float64 // absolute input energy, integral of the absolute acceleration times dug
+	KineticEnergy         []float64 // relative kinetic energy, v2/2
+	AbsoluteKineticEnergy []float64 // absolute kinetic energy, (v+vg)2/2
+	DampingEnergy         []float64 // energy dissipated by viscous damping
+	StrainEnergy          []float64 // recoverable elastic strain energy
+	HystereticEnergy      []float64 // energy dissipated by yielding
+}
+
+// EnergySpectraData holds the energy spectra per unit mass. Input and kinetic energies are the peaks over the
+// motion, damping and hysteretic energies are the values at its end.
+type EnergySpectraData struct {
+	Periods                      []float64
+	InputEnergies                []float64 // cm2/s2
+	AbsoluteInputEnergies        []float64 // cm2/s2
+	KineticEnergies              []float64 // cm2/s2
+	DampingEnergies              []float64 // cm2/s2
+	HystereticEnergies           []float64 // cm2/s2
+	EquivalentVelocities         []float64 // sqrt(2 InputEnergy) (cm/s)
+	AbsoluteEquivalentVelocities []float64 // sqrt(2 AbsoluteInputEnergy) (cm/s)
+}
+
+// energyResponse integrates the energy balance of the oscillator with the trapezoidal rule over the steps of its
+// integration. Accelerations are in g and the energies are converted from g2*s2 to cm2/s2.
+func energyResponse(accelerations []float64, dt float64, oscillator bilinearOscillator) (*EnergyResponse, error) {
+	const scale = 981 * 981
+	n := len(accelerations)
+	response := EnergyResponse{
+		Times:                 make([]float64, n),
+		InputEnergy:           make([]float64, n),
+		AbsoluteInputEnergy:   make([]float64, n),
+		KineticEnergy:         make([]float64, n),
+		AbsoluteKineticEnergy: make([]float64, n),
+		DampingEnergy:         make([]float64, n),
+		StrainEnergy:          make([]float64, n),
+		HystereticEnergy:      make([]float64, n),
+	}
+	for i := range response.Times {
+		response.Times[i] = float64(i) * dt
+	}
+
+	k := oscillator.omega * oscillator.omega
+	c := 2 * oscillator.damping * oscillator.omega
+	previousGround := accelerations[0]
+	var previousU, previousV, previousFs, groundVelocity float64
+	var input, damping, strain float64
+	numSteps, sample := 0, 1
+	_, err := oscillator.respond(accelerations, dt, func(h, ground, u, v, fs float64) {
+		du := u - previousU
+		input -= (previousGround + ground) / 2 * du
+		damping += c * (previousV + v) / 2 * du
+		strain += (previousFs + fs) / 2 * du
+		groundVelocity += (previousGround + ground) / 2 * h
+		previousGround, previousU, previousV, previousFs = ground, u, v, fs
+
+		// the time step of the oscillator divides the time step of the motion
+		numSteps++
+		if float64(numSteps)*h < float64(sample)*dt*(1-1e-9) {
+			return
+		}
+		kinetic := v * v / 2
+		absoluteKinetic := (v + groundVelocity) * (v + groundVelocity) / 2
+		recoverable := fs * fs / (2 * k)
+		response.InputEnergy[sample] = input * scale
+		response.AbsoluteInputEnergy[sample] = (input + absoluteKinetic - kinetic) * scale
+		response.KineticEnergy[sample] = kinetic * scale
+		response.AbsoluteKineticEnergy[sample] = absoluteKinetic * scale
+		response.DampingEnergy[sample] = damping * scale
+		response.StrainEnergy[sample] = recoverable * scale
+		response.HystereticEnergy[sample] = (strain - recoverable) * scale
+		sample++
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func energyOscillator(
+	accelerations []float64, dt, period, reductionFactor float64, options InelasticOptions,
+) (bilinearOscillator, error) {
+	if reductionFactor == 1 {
+		return newBilinearOscillator(period, options), nil
+	}
+	oscillator, _, err := constantStrengthOscillator(accelerations, dt, period, reductionFactor, options)
+	return oscillator, err
+}
+
+// CalcEnergyResponse returns the energy time histories of the oscillator whose yield strength is its elastic strength
+// divided by the reduction factor. A reduction factor of 1 gives a linear elastic oscillator.
+func CalcEnergyResponse(
+	motion ts.MotionData, period, reductionFactor float64, options InelasticOptions,
+) (*EnergyResponse, error) {
+	accelerations, dt := motion.Accelerations, motion.TimeStep
+	if err := checkInelasticInput(accelerations, dt, []float64{period}, options); err != nil {
+		return nil, err
+	}
+	if reductionFactor < 1 {
+		return nil, errors.New("reduction factor must be at least 1")
+	}
+	oscillator, err := energyOscillator(accelerations, dt, period, reductionFactor, options)
+	if err != nil {
+		return nil, err
+	}
+	return energyResponse(accelerations, dt, oscillator)
+}
+
+// CalcEnergySpectra returns the energy spectra of the oscillators whose yield strength is their elastic strength
+// divided by the reduction factor. A reduction factor of 1 gives linear elastic oscillators. The equivalent
+// velocities are computed from the peak input energies.
+func CalcEnergySpectra(
+	motion ts.MotionData, periods []float64, reductionFactor float64, options InelasticOptions,
+) (*EnergySpectraData, error) {
+	accelerations, dt := motion.Accelerations, motion.TimeStep
+	if err := checkInelasticInput(accelerations, dt, periods, options); err != nil {
+		return nil, err
+	}
+	if reductionFactor < 1 {
+		return nil, errors.New("reduction factor must be at least 1")
+	}
+	n := len(periods)
+	spectra := EnergySpectraData{
+		Periods:                      append([]float64{}, periods...),
+		InputEnergies:                make([]float64, n),
+		AbsoluteInputEnergies:        make([]float64, n),
+		KineticEnergies:              make([]float64, n),
+		DampingEnergies:              make([]float64, n),
+		HystereticEnergies:           make([]float64, n),
+		EquivalentVelocities:         make([]float64, n),
+		AbsoluteEquivalentVelocities: make([]float64, n),
+	}
+	errs := make([]error, n)
+	numeric.ParallelFor(n, func(j int) {
+		oscillator, err := energyOscillator(accelerations, dt, periods[j], reductionFactor, options)
+		if err != nil {
+			errs[j] = err
+			return
+		}
+		response, err := energyResponse(accelerations, dt, oscillator)
+		if err != nil {
+			errs[j] = err
+			return
+		}
+		last := len(accelerations) - 1
+		for i := range response.Times {
+			spectra.InputEnergies[j] = math.Max(spectra.InputEnergies[j], response.InputEnergy[i])
+			spectra.AbsoluteInputEnergies[j] = math.Max(spectra.AbsoluteInputEnergies[j], response.AbsoluteInputEnergy[i])
+			spectra.KineticEnergies[j] = math.Max(spectra.KineticEnergies[j], response.KineticEnergy[i])
+		}
+		spectra.DampingEnergies[j] = response.DampingEnergy[last]
+		spectra.HystereticEnergies[j] = response.HystereticEnergy[last]
+		spectra.EquivalentVelocities[j] = math.Sqrt(2 * spectra.InputEnergies[j])
+		spectra.AbsoluteEquivalentVelocities[j] = math.Sqrt(2 * spectra.AbsoluteInputEnergies[j])
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return &spectra, nil
+}
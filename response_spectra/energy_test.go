@@ -0,0 +1,65 @@
+package response_spectra
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+func TestCalcEnergyResponse(t *testing.T) {
+	motion := ts.MotionData{Accelerations: td.TestMotion["Accelerations"].([]float64), TimeStep: 0.005}
+	options := DefaultInelasticOptions()
+	for _, reductionFactor := range []float64{1, 4} {
+		response, err := CalcEnergyResponse(motion, 0.5, reductionFactor, options)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(response.Times) != len(motion.Accelerations) {
+			t.Fatalf("Expected energies at each time step of the motion")
+		}
+		var maxInput, maxError float64
+		for i := range response.Times {
+			balance := response.KineticEnergy[i] + response.DampingEnergy[i] + response.StrainEnergy[i] +
+				response.HystereticEnergy[i]
+			maxInput = math.Max(maxInput, math.Abs(response.InputEnergy[i]))
+			maxError = math.Max(maxError, math.Abs(balance-response.InputEnergy[i]))
+		}
+		if maxError > 1e-3*maxInput {
+			t.Errorf("Expected energy balance with R = %.0f, got error %e of %e", reductionFactor, maxError, maxInput)
+		}
+		last := len(response.Times) - 1
+		if reductionFactor == 1 && math.Abs(response.HystereticEnergy[last]) > 1e-9*maxInput {
+			t.Errorf("Expected no hysteretic energy of an elastic oscillator, got %f", response.HystereticEnergy[last])
+		}
+		if reductionFactor == 4 && response.HystereticEnergy[last] < 0.1*response.InputEnergy[last] {
+			t.Errorf("Expected hysteretic energy of a yielding oscillator, got %f", response.HystereticEnergy[last])
+		}
+	}
+	if _, err := CalcEnergyResponse(motion, 0.5, 0.5, options); err == nil {
+		t.Errorf("Expected error for reduction factor below 1")
+	}
+}
+
+func TestCalcEnergySpectra(t *testing.T) {
+	motion := ts.MotionData{Accelerations: td.TestMotion["Accelerations"].([]float64), TimeStep: 0.005}
+	periods := []float64{0.2, 1, 3}
+	spectra, err := CalcEnergySpectra(motion, periods, 2, DefaultInelasticOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for j, period := range periods {
+		if spectra.InputEnergies[j] <= 0 || spectra.AbsoluteInputEnergies[j] <= 0 {
+			t.Errorf("Expected positive input energies at %.1f s", period)
+		}
+		expected := math.Sqrt(2 * spectra.InputEnergies[j])
+		if spectra.EquivalentVelocities[j] != expected {
+			t.Errorf("Expected equivalent velocity %f at %.1f s, got %f", expected, period, spectra.EquivalentVelocities[j])
+		}
+		dissipated := spectra.DampingEnergies[j] + spectra.HystereticEnergies[j]
+		if dissipated > spectra.InputEnergies[j]*(1+1e-3) {
+			t.Errorf("Expected dissipated energy below the input energy at %.1f s", period)
+		}
+	}
+}
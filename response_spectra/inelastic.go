@@ -0,0 +1,297 @@
+package response_spectra
+
+import (
+	"errors"
+	"math"
+
+	"github.com/geoport/GoQuakeLib/internal/newmark"
+	"github.com/geoport/GoQuakeLib/internal/numeric"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// InelasticOptions controls the nonlinear oscillators of the inelastic spectra.
+type InelasticOptions struct {
+	Model          string  // "epp" (elastic-perfectly-plastic) or "bilinear"
+	HardeningRatio float64 // post-yield to elastic stiffness ratio of the "bilinear" model
+	Damping        float64 // viscous damping ratio
+	Ductility      float64 // target displacement ductility of the constant-ductility spectra
+}
+
+// InelasticSpectraData holds the constant-ductility or constant-strength spectra of nonlinear oscillators.
+type InelasticSpectraData struct {
+	Periods               []float64
+	SpectralAccelerations []float64 // peak absolute acceleration (g)
+	SpectralDisplacements []float64 // peak relative displacement (cm)
+	YieldAccelerations    []float64 // yield strength per unit mass (g)
+	YieldDisplacements    []float64 // yield displacement (cm)
+	ReductionFactors      []float64 // yield-strength reduction factor R = elastic strength / yield strength
+	Ductilities           []float64 // peak displacement / yield displacement
+	YieldExcursions       []int     // number of times the oscillator enters the post-yield branch
+}
+
+// DefaultInelasticOptions returns elastic-perfectly-plastic oscillators with 5% damping and a target ductility of 2.
+func DefaultInelasticOptions() InelasticOptions {
+	return InelasticOptions{Model: "epp", Damping: 0.05, Ductility: 2}
+}
+
+// bilinearOscillator is a unit mass oscillator with a bilinear, kinematic hardening force-deformation relation.
+type bilinearOscillator struct {
+	omega      float64
+	damping    float64
+	yieldForce float64 // per unit mass (g), infinite for a linear oscillator
+	hardening  float64
+}
+
+// inelasticResponse holds the peak response of a nonlinear oscillator.
+type inelasticResponse struct {
+	maxDisplacement float64
+	maxAcceleration float64 // absolute
+	excursions      int
+}
+
+// respond integrates the oscillator under the ground accelerations with the nonlinear Newmark engine, each time step
+// divided into newmark.Substeps steps. If step is not nil it is called after each step with the time step, the ground
+// acceleration at the end of the step, the displacement, velocity and restoring force at the end of the step.
+func (o *bilinearOscillator) respond(
+	accelerations []float64, dt float64, step func(h, ground, u, v, fs float64),
+) (inelasticResponse, error) {
+	spring := newmark.NewBilinear(o.omega*o.omega, o.yieldForce, o.hardening)
+	c := 2 * o.damping * o.omega
+	oscillator := newmark.Oscillator{Mass: 1, Damping: c, Spring: spring}
+	numSteps := newmark.Substeps(dt, 2*math.Pi/o.omega)
+	h := dt / float64(numSteps)
+
+	var response inelasticResponse
+	state := newmark.State{A: -accelerations[0]}
+	for i := 1; i < len(accelerations); i++ {
+		for k := 1; k <= numSteps; k++ {
+			ground0 := accelerations[i-1] + float64(k-1)/float64(numSteps)*(accelerations[i]-accelerations[i-1])
+			ground1 := accelerations[i-1] + float64(k)/float64(numSteps)*(accelerations[i]-accelerations[i-1])
+			yielding := spring.Yielding()
+			if err := oscillator.Step(&state, ground0, ground1, h); err != nil {
+				return response, err
+			}
+			if spring.Yielding() && !yielding {
+				response.excursions++
+			}
+			response.maxDisplacement = math.Max(response.maxDisplacement, math.Abs(state.U))
+			response.maxAcceleration = math.Max(response.maxAcceleration, math.Abs(c*state.V+state.F))
+			if step != nil {
+				step(h, ground1, state.U, state.V, state.F)
+			}
+		}
+	}
+	return response, nil
+}
+
+func checkInelasticInput(accelerations []float64, dt float64, periods []float64, options InelasticOptions) error {
+	if len(accelerations) < 2 {
+		return errors.New("at least two acceleration samples are required")
+	}
+	if dt <= 0 {
+		return errors.New("time step must be a positive number")
+	}
+	if len(periods) == 0 {
+		return errors.New("periods are empty")
+	}
+	for _, period := range periods {
+		if period <= 0 {
+			return errors.New("periods must be positive")
+		}
+	}
+	if options.Damping < 0 || options.Damping >= 1 {
+		return errors.New("damping ratio must be in [0, 1)")
+	}
+	switch options.Model {
+	case "epp":
+	case "bilinear":
+		if options.HardeningRatio < 0 || options.HardeningRatio >= 1 {
+			return errors.New("hardening ratio must be in [0, 1)")
+		}
+	default:
+		return errors.New("hysteretic model not supported")
+	}
+	return nil
+}
+
+func newBilinearOscillator(period float64, options InelasticOptions) bilinearOscillator {
+	oscillator := bilinearOscillator{omega: 2 * math.Pi / period, damping: options.Damping, yieldForce: math.Inf(1)}
+	if options.Model == "bilinear" {
+		oscillator.hardening = options.HardeningRatio
+	}
+	return oscillator
+}
+
+// inelasticPoint holds the response of one oscillator of the inelastic spectra.
+type inelasticPoint struct {
+	response        inelasticResponse
+	yieldForce      float64
+	elasticStrength float64
+}
+
+// newInelasticSpectraData returns the spectra of the oscillators. The displacements are converted from g*s2 to cm.
+func newInelasticSpectraData(periods []float64, points []inelasticPoint) *InelasticSpectraData {
+	n := len(periods)
+	spectra := InelasticSpectraData{
+		Periods:               append([]float64{}, periods...),
+		SpectralAccelerations: make([]float64, n),
+		SpectralDisplacements: make([]float64, n),
+		YieldAccelerations:    make([]float64, n),
+		YieldDisplacements:    make([]float64, n),
+		ReductionFactors:      make([]float64, n),
+		Ductilities:           make([]float64, n),
+		YieldExcursions:       make([]int, n),
+	}
+	for j, point := range points {
+		omega := 2 * math.Pi / periods[j]
+		yieldDisplacement := point.yieldForce / (omega * omega)
+		spectra.SpectralAccelerations[j] = point.response.maxAcceleration
+		spectra.SpectralDisplacements[j] = point.response.maxDisplacement * 981
+		spectra.YieldAccelerations[j] = point.yieldForce
+		spectra.YieldDisplacements[j] = yieldDisplacement * 981
+		spectra.ReductionFactors[j] = point.elasticStrength / point.yieldForce
+		spectra.Ductilities[j] = point.response.maxDisplacement / yieldDisplacement
+		spectra.YieldExcursions[j] = point.response.excursions
+	}
+	return &spectra
+}
+
+// constantDuctilityPoint finds the largest yield strength at which the ductility demand reaches the target. The
+// strength ratio is scanned downwards from the elastic strength on a logarithmic grid, since ductility is not a
+// monotonic function of strength, and the first bracket is refined by bisection. Records without response have no
+// elastic strength to reduce.
+func constantDuctilityPoint(
+	accelerations []float64, dt, period float64, options InelasticOptions,
+) (inelasticPoint, error) {
+	const numRatios = 100
+	const minRatio = 1e-3
+	oscillator := newBilinearOscillator(period, options)
+	elastic, err := oscillator.respond(accelerations, dt, nil)
+	if err != nil {
+		return inelasticPoint{}, err
+	}
+	k := oscillator.omega * oscillator.omega
+	elasticStrength := k * elastic.maxDisplacement
+	if !(elasticStrength > 0) {
+		return inelasticPoint{}, errors.New("elastic strength must be positive")
+	}
+
+	ductilityAt := func(ratio float64) (float64, inelasticResponse, error) {
+		oscillator.yieldForce = ratio * elasticStrength
+		response, err := oscillator.respond(accelerations, dt, nil)
+		return response.maxDisplacement * k / oscillator.yieldForce, response, err
+	}
+
+	upper := 1.
+	lower := upper
+	found := false
+	for i := 1; i <= numRatios; i++ {
+		ratio := math.Pow(minRatio, float64(i)/numRatios)
+		ductility, _, err := ductilityAt(ratio)
+		if err != nil {
+			return inelasticPoint{}, err
+		}
+		if ductility >= options.Ductility {
+			lower, found = ratio, true
+			break
+		}
+		upper = ratio
+	}
+	if !found {
+		lower = minRatio
+	}
+	for i := 0; i < 30 && upper/lower > 1+1e-6; i++ {
+		middle := math.Sqrt(upper * lower)
+		ductility, _, err := ductilityAt(middle)
+		if err != nil {
+			return inelasticPoint{}, err
+		}
+		if ductility >= options.Ductility {
+			lower = middle
+		} else {
+			upper = middle
+		}
+	}
+	_, response, err := ductilityAt(lower)
+	return inelasticPoint{response: response, yieldForce: lower * elasticStrength, elasticStrength: elasticStrength}, err
+}
+
+// firstError returns the first error that is not nil.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CalcConstantDuctilitySpectra returns the inelastic spectra of oscillators whose yield strength gives the target
+// ductility of the options. Where several strengths give the target ductility the largest one is used.
+func CalcConstantDuctilitySpectra(
+	accelerations []float64, dt float64, periods []float64, options InelasticOptions,
+) (*InelasticSpectraData, error) {
+	if err := checkInelasticInput(accelerations, dt, periods, options); err != nil {
+		return nil, err
+	}
+	if options.Ductility < 1 {
+		return nil, errors.New("target ductility must be at least 1")
+	}
+	points := make([]inelasticPoint, len(periods))
+	errs := make([]error, len(periods))
+	numeric.ParallelFor(len(periods), func(j int) {
+		points[j], errs[j] = constantDuctilityPoint(accelerations, dt, periods[j], options)
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return newInelasticSpectraData(periods, points), nil
+}
+
+// constantStrengthOscillator returns the oscillator whose yield strength is the elastic strength divided by the
+// reduction factor, with the elastic strength. Records without response have no elastic strength to reduce.
+func constantStrengthOscillator(
+	accelerations []float64, dt, period, reductionFactor float64, options InelasticOptions,
+) (bilinearOscillator, float64, error) {
+	oscillator := newBilinearOscillator(period, options)
+	elastic, err := oscillator.respond(accelerations, dt, nil)
+	if err != nil {
+		return oscillator, 0, err
+	}
+	elasticStrength := oscillator.omega * oscillator.omega * elastic.maxDisplacement
+	if !(elasticStrength > 0) {
+		return oscillator, 0, errors.New("elastic strength must be positive")
+	}
+	oscillator.yieldForce = elasticStrength / reductionFactor
+	return oscillator, elasticStrength, nil
+}
+
+// CalcConstantStrengthSpectra returns the inelastic spectra of oscillators whose yield strength is the elastic
+// strength divided by the reduction factor, giving the ductility demand of each period. The target ductility of the
+// options is not used.
+func CalcConstantStrengthSpectra(
+	motion ts.MotionData, periods []float64, reductionFactor float64, options InelasticOptions,
+) (*InelasticSpectraData, error) {
+	accelerations, dt := motion.Accelerations, motion.TimeStep
+	if err := checkInelasticInput(accelerations, dt, periods, options); err != nil {
+		return nil, err
+	}
+	if reductionFactor < 1 {
+		return nil, errors.New("reduction factor must be at least 1")
+	}
+	points := make([]inelasticPoint, len(periods))
+	errs := make([]error, len(periods))
+	numeric.ParallelFor(len(periods), func(j int) {
+		oscillator, elasticStrength, err := constantStrengthOscillator(accelerations, dt, periods[j], reductionFactor, options)
+		if err != nil {
+			errs[j] = err
+			return
+		}
+		points[j] = inelasticPoint{yieldForce: oscillator.yieldForce, elasticStrength: elasticStrength}
+		points[j].response, errs[j] = oscillator.respond(accelerations, dt, nil)
+	})
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return newInelasticSpectraData(periods, points), nil
+}
@@ -0,0 +1,105 @@
+package response_spectra
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+func TestBilinearOscillator(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := []float64{0.2, 1, 3}
+	elastic := ResponseSpectra(testAcceleration, 0.005, append([]float64{}, periods...), 0.05)
+	for j, period := range periods {
+		oscillator := newBilinearOscillator(period, DefaultInelasticOptions())
+		response, err := oscillator.respond(testAcceleration, 0.005, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		displacement := response.maxDisplacement * 981
+		if math.Abs(displacement-elastic.SpectralDisplacements[j]) > 0.01*elastic.SpectralDisplacements[j] {
+			t.Errorf("Expected elastic displacement %f at %.1f s, got %f", elastic.SpectralDisplacements[j], period, displacement)
+		}
+		if response.excursions != 0 {
+			t.Errorf("Expected no yield excursions of a linear oscillator")
+		}
+	}
+}
+
+func TestCalcConstantDuctilitySpectra(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := []float64{0.1, 0.5, 1, 2}
+	for _, options := range []InelasticOptions{
+		{Model: "epp", Damping: 0.05, Ductility: 4},
+		{Model: "bilinear", HardeningRatio: 0.05, Damping: 0.05, Ductility: 4},
+	} {
+		spectra, err := CalcConstantDuctilitySpectra(testAcceleration, 0.005, periods, options)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for j, period := range periods {
+			if math.Abs(spectra.Ductilities[j]-4) > 0.01*4 {
+				t.Errorf("Expected %s ductility of 4 at %.1f s, got %f", options.Model, period, spectra.Ductilities[j])
+			}
+			if spectra.ReductionFactors[j] <= 1 || spectra.YieldExcursions[j] == 0 {
+				t.Errorf("Expected %s oscillator at %.1f s to yield", options.Model, period)
+			}
+			if math.Abs(spectra.SpectralDisplacements[j]-4*spectra.YieldDisplacements[j]) > 0.01*spectra.SpectralDisplacements[j] {
+				t.Errorf("Expected peak displacement of 4 times the yield displacement at %.1f s", period)
+			}
+		}
+		// strength reductions are smaller at short periods than at long periods
+		if spectra.ReductionFactors[0] >= spectra.ReductionFactors[3] {
+			t.Errorf("Expected smaller %s reduction factor at 0.1 s than at 2 s, got %f and %f",
+				options.Model, spectra.ReductionFactors[0], spectra.ReductionFactors[3])
+		}
+	}
+
+	options := DefaultInelasticOptions()
+	options.Ductility = 0.5
+	if _, err := CalcConstantDuctilitySpectra(testAcceleration, 0.005, periods, options); err == nil {
+		t.Errorf("Expected error for ductility below 1")
+	}
+	options = InelasticOptions{Model: "bilinear", HardeningRatio: 1, Ductility: 2}
+	if _, err := CalcConstantDuctilitySpectra(testAcceleration, 0.005, periods, options); err == nil {
+		t.Errorf("Expected error for hardening ratio of 1")
+	}
+	if _, err := CalcConstantDuctilitySpectra(testAcceleration, 0.005, []float64{0}, DefaultInelasticOptions()); err == nil {
+		t.Errorf("Expected error for zero period")
+	}
+	if _, err := CalcConstantDuctilitySpectra(make([]float64, 100), 0.005, periods, DefaultInelasticOptions()); err == nil {
+		t.Errorf("Expected error for a record without response")
+	}
+}
+
+func TestCalcConstantStrengthSpectra(t *testing.T) {
+	motion := ts.MotionData{Accelerations: td.TestMotion["Accelerations"].([]float64), TimeStep: 0.005}
+	periods := []float64{0.3, 1}
+	options := InelasticOptions{Model: "epp", Damping: 0.05, Ductility: 3}
+	ductilitySpectra, _ := CalcConstantDuctilitySpectra(motion.Accelerations, motion.TimeStep, periods, options)
+	for j, period := range periods {
+		spectra, err := CalcConstantStrengthSpectra(motion, periods[j:j+1], ductilitySpectra.ReductionFactors[j], options)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if math.Abs(spectra.Ductilities[0]-3) > 0.01*3 {
+			t.Errorf("Expected ductility demand of 3 at %.1f s, got %f", period, spectra.Ductilities[0])
+		}
+	}
+
+	spectra, _ := CalcConstantStrengthSpectra(motion, periods, 1, options)
+	for j := range periods {
+		if spectra.Ductilities[j] > 1+1e-6 || spectra.YieldExcursions[j] != 0 {
+			t.Errorf("Expected elastic response for a reduction factor of 1, got ductility %f", spectra.Ductilities[j])
+		}
+	}
+	if _, err := CalcConstantStrengthSpectra(motion, periods, 0.5, options); err == nil {
+		t.Errorf("Expected error for reduction factor below 1")
+	}
+	zero := ts.MotionData{Accelerations: make([]float64, 100), TimeStep: 0.005}
+	if _, err := CalcConstantStrengthSpectra(zero, periods, 2, options); err == nil {
+		t.Errorf("Expected error for a record without response")
+	}
+}
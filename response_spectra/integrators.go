@@ -0,0 +1,251 @@
+package response_spectra
+
+import (
+	"errors"
+	"math"
+
+	"github.com/geoport/GoQuakeLib/fft"
+)
+
+// SpectraOptions selects the time integration scheme of the oscillators.
+type SpectraOptions struct {
+	// Method is "nigam_jennings" (piecewise exact, default), "newmark_average", "newmark_linear",
+	// "central_difference" or "frequency_domain".
+	Method string
+	// CumulativePeaks keeps the running peaks of each period up to each sample of the record.
+	CumulativePeaks bool
+}
+
+// DefaultSpectraOptions returns the piecewise exact Nigam-Jennings integration used by ResponseSpectra.
+func DefaultSpectraOptions() SpectraOptions {
+	return SpectraOptions{Method: "nigam_jennings"}
+}
+
+// peakFunc records the relative displacement, relative velocity and absolute acceleration of the oscillator with the
+// given period.
+type peakFunc func(period float64, peaks *responsePeaks)
+
+func checkSpectraInput(accelerations []float64, dt float64, periods []float64, damping float64) error {
+	if len(accelerations) < 2 {
+		return errors.New("at least two acceleration samples are required")
+	}
+	if dt <= 0 {
+		return errors.New("time step must be a positive number")
+	}
+	if len(periods) == 0 {
+		return errors.New("periods are empty")
+	}
+	for _, period := range periods {
+		if period < 0 {
+			return errors.New("periods must not be negative")
+		}
+	}
+	if damping < 0 || damping >= 1 {
+		return errors.New("damping ratio must be in [0, 1)")
+	}
+	return nil
+}
+
+// CalcResponseSpectra returns the response spectra computed with the integration method of the options. The periods
+// are not modified; a zero period is treated as a rigid oscillator whose absolute acceleration is the ground
+// acceleration. The conditionally stable "newmark_linear" and "central_difference" methods subdivide the time step,
+// interpolating the ground acceleration linearly, where it exceeds their stability limit.
+func CalcResponseSpectra(
+	accelerations []float64, dt float64, periods []float64, damping float64, options SpectraOptions,
+) (*ResponseSpectraData, error) {
+	spectra, err := CalcMultiDampingSpectra(accelerations, dt, periods, []float64{damping}, options)
+	if err != nil {
+		return nil, err
+	}
+	return spectra[0], nil
+}
+
+// CalcMultiDampingSpectra returns the response spectra of each damping ratio, sharing a single pool of workers over
+// all the oscillators. See CalcResponseSpectra.
+func CalcMultiDampingSpectra(
+	accelerations []float64, dt float64, periods, dampings []float64, options SpectraOptions,
+) ([]*ResponseSpectraData, error) {
+	if len(dampings) == 0 {
+		return nil, errors.New("damping ratios are empty")
+	}
+	peakFuncs := make([]peakFunc, len(dampings))
+	for d, damping := range dampings {
+		if err := checkSpectraInput(accelerations, dt, periods, damping); err != nil {
+			return nil, err
+		}
+		var err error
+		if peakFuncs[d], err = getPeakFunc(accelerations, dt, periods, damping, options.Method); err != nil {
+			return nil, err
+		}
+	}
+
+	numPeriods := len(periods)
+	peaks := parallelPeaks(numPeriods*len(dampings), func(index int) *responsePeaks {
+		period := periods[index%numPeriods]
+		if period == 0 {
+			return rigidPeaks(accelerations, options.CumulativePeaks)
+		}
+		p := newResponsePeaks(len(accelerations), options.CumulativePeaks)
+		peakFuncs[index/numPeriods](period, p)
+		return p
+	})
+
+	spectra := make([]*ResponseSpectraData, len(dampings))
+	for d := range dampings {
+		start, end := d*numPeriods, (d+1)*numPeriods
+		spectra[d] = spectraFromPeaks(append([]float64{}, periods...), peaks[start:end], dt)
+	}
+	return spectra, nil
+}
+
+func getPeakFunc(
+	accelerations []float64, dt float64, periods []float64, damping float64, method string,
+) (peakFunc, error) {
+	switch method {
+	case "", "nigam_jennings":
+		return func(period float64, peaks *responsePeaks) {
+			constants, _, omega2 := nigamJenningsConstants(dt, []float64{period}, damping)
+			c := periodConstants(constants, omega2, 0)
+			c.peaks(accelerations, dt, peaks)
+		}, nil
+	case "newmark_average":
+		return newmarkPeaks(accelerations, dt, damping, 0.25), nil
+	case "newmark_linear":
+		return newmarkPeaks(accelerations, dt, damping, 1./6), nil
+	case "central_difference":
+		return centralDifferencePeaks(accelerations, dt, damping), nil
+	case "frequency_domain":
+		return frequencyDomainPeaks(accelerations, dt, periods, damping)
+	default:
+		return nil, errors.New("integration method not supported")
+	}
+}
+
+// subdivide returns the ground accelerations linearly interpolated on a time step that is at most maxRatio times
+// the period, with the new time step.
+func subdivide(accelerations []float64, dt, period, maxRatio float64) ([]float64, float64) {
+	numDivisions := int(math.Ceil(dt / (maxRatio * period)))
+	if numDivisions <= 1 {
+		return accelerations, dt
+	}
+	divided := make([]float64, (len(accelerations)-1)*numDivisions+1)
+	for i := 0; i < len(accelerations)-1; i++ {
+		for k := 0; k < numDivisions; k++ {
+			fraction := float64(k) / float64(numDivisions)
+			divided[i*numDivisions+k] = accelerations[i] + fraction*(accelerations[i+1]-accelerations[i])
+		}
+	}
+	divided[len(divided)-1] = accelerations[len(accelerations)-1]
+	return divided, dt / float64(numDivisions)
+}
+
+// newmarkPeaks integrates the oscillators with the incremental Newmark method with gamma = 1/2 (Chopra, Table
+// 5.4.2). beta = 1/4 is the unconditionally stable average acceleration method and beta = 1/6 the linear
+// acceleration method, stable for dt/T <= sqrt(3)/pi.
+func newmarkPeaks(accelerations []float64, dt, damping, beta float64) peakFunc {
+	const gamma = 0.5
+	return func(period float64, peaks *responsePeaks) {
+		ground, h := accelerations, dt
+		if beta < 0.25 {
+			ground, h = subdivide(accelerations, dt, period, math.Sqrt(3)/math.Pi)
+		}
+		peaks.setTimeStep(h, dt)
+		omega := 2 * math.Pi / period
+		k := omega * omega
+		c := 2 * damping * omega
+		kHat := k + gamma/(beta*h)*c + 1/(beta*h*h)
+		aCoefficient := 1/(beta*h) + gamma/beta*c
+		bCoefficient := 1/(2*beta) + h*(gamma/(2*beta)-1)*c
+
+		var u, v float64
+		a := -ground[0]
+		for i := 0; i < len(ground)-1; i++ {
+			dp := -(ground[i+1] - ground[i]) + aCoefficient*v + bCoefficient*a
+			du := dp / kHat
+			dv := gamma/(beta*h)*du - gamma/beta*v + h*(1-gamma/(2*beta))*a
+			da := du/(beta*h*h) - v/(beta*h) - a/(2*beta)
+			u, v, a = u+du, v+dv, a+da
+			peaks.record(i+1, u, v, -(c*v + k*u))
+		}
+	}
+}
+
+// centralDifferencePeaks integrates the oscillators with the central difference method (Chopra, Table 5.3.1),
+// stable for dt/T <= 1/pi. Velocities are central differences of the displacements.
+func centralDifferencePeaks(accelerations []float64, dt, damping float64) peakFunc {
+	return func(period float64, peaks *responsePeaks) {
+		ground, h := subdivide(accelerations, dt, period, 1/math.Pi)
+		peaks.setTimeStep(h, dt)
+		omega := 2 * math.Pi / period
+		k := omega * omega
+		c := 2 * damping * omega
+		kHat := 1/(h*h) + c/(2*h)
+		aCoefficient := 1/(h*h) - c/(2*h)
+		bCoefficient := k - 2/(h*h)
+
+		// u(-dt) from the initial rest state with the acceleration -ground[0]
+		previous := -ground[0] * h * h / 2
+		var u float64
+		for i := 0; i < len(ground); i++ {
+			next := (-ground[i] - aCoefficient*previous - bCoefficient*u) / kHat
+			if i > 0 {
+				v := (next - previous) / (2 * h)
+				peaks.record(i, u, v, -(c*v + k*u))
+			}
+			previous, u = u, next
+		}
+	}
+}
+
+// frequencyDomainPeaks computes the responses as the inverse transform of the ground acceleration spectrum
+// multiplied by the transfer functions of the oscillators. The record is zero-padded until the free vibration of
+// the longest period decays to 1% of its amplitude, up to four times the record length, to limit wrap-around.
+func frequencyDomainPeaks(accelerations []float64, dt float64, periods []float64, damping float64) (peakFunc, error) {
+	if damping == 0 {
+		return nil, errors.New("frequency domain method requires a positive damping ratio")
+	}
+	length := len(accelerations)
+	var maxPeriod float64
+	for _, period := range periods {
+		maxPeriod = math.Max(maxPeriod, period)
+	}
+	padding := 4 * length
+	if maxPeriod > 0 {
+		decay := math.Log(100) / (damping * 2 * math.Pi / maxPeriod)
+		padding = int(math.Min(math.Ceil(decay/dt), float64(padding)))
+	}
+	numFFT := 1
+	for numFFT < length+padding {
+		numFFT *= 2
+	}
+	plan, err := fft.NewRealPlan(numFFT)
+	if err != nil {
+		return nil, err
+	}
+	padded := make([]float64, numFFT)
+	copy(padded, accelerations)
+	ground := make([]complex128, numFFT/2+1)
+	plan.Forward(ground, padded)
+
+	return func(period float64, peaks *responsePeaks) {
+		omegaN := 2 * math.Pi / period
+		displacement := make([]complex128, len(ground))
+		velocity := make([]complex128, len(ground))
+		acceleration := make([]complex128, len(ground))
+		for k, value := range ground {
+			omega := 2 * math.Pi * float64(k) / (float64(numFFT) * dt)
+			u := -value / complex(omegaN*omegaN-omega*omega, 2*damping*omegaN*omega)
+			displacement[k] = u
+			velocity[k] = complex(0, omega) * u
+			acceleration[k] = -complex(omegaN*omegaN, 2*damping*omegaN*omega) * u
+		}
+		var signals [3][]float64
+		for r, response := range [][]complex128{displacement, velocity, acceleration} {
+			signals[r] = make([]float64, numFFT)
+			plan.Inverse(signals[r], response)
+		}
+		for i := 0; i < length; i++ {
+			peaks.record(i, signals[0][i], signals[1][i], signals[2][i])
+		}
+	}, nil
+}
@@ -0,0 +1,73 @@
+package response_spectra
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+func TestCalcResponseSpectraMethods(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := np.Arange(0.1, 4, 0.1)
+	reference := ResponseSpectra(testAcceleration, 0.005, append([]float64{}, periods...), 0.05)
+
+	output, err := CalcResponseSpectra(testAcceleration, 0.005, periods, 0.05, DefaultSpectraOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(output.SpectralAccelerations, reference.SpectralAccelerations) {
+		t.Errorf("Expected nigam_jennings spectra identical to ResponseSpectra")
+	}
+
+	for _, method := range []string{"newmark_average", "newmark_linear", "central_difference", "frequency_domain"} {
+		output, err = CalcResponseSpectra(testAcceleration, 0.005, periods, 0.05, SpectraOptions{Method: method})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for j := range periods {
+			for name, pair := range map[string][2]float64{
+				"SA": {output.SpectralAccelerations[j], reference.SpectralAccelerations[j]},
+				"SV": {output.SpectralVelocities[j], reference.SpectralVelocities[j]},
+				"SD": {output.SpectralDisplacements[j], reference.SpectralDisplacements[j]},
+			} {
+				if math.Abs(pair[0]-pair[1]) > 0.03*pair[1] {
+					t.Errorf("Expected %s %s at %.1f s within 3%% of %f, got %f", method, name, periods[j], pair[1], pair[0])
+				}
+			}
+		}
+	}
+}
+
+func TestCalcResponseSpectraPeriods(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := []float64{0, 0.001, 1}
+	for _, method := range []string{"nigam_jennings", "newmark_linear", "central_difference"} {
+		output, err := CalcResponseSpectra(testAcceleration, 0.005, periods, 0.05, SpectraOptions{Method: method})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		pga := np.Max(np.Abs(testAcceleration))
+		if output.SpectralAccelerations[0] != pga || output.PseudoAccelerations[0] != pga {
+			t.Errorf("Expected %s spectral acceleration of the PGA at zero period, got %f", method, output.SpectralAccelerations[0])
+		}
+		if math.Abs(output.SpectralAccelerations[1]-pga) > 0.05*pga {
+			t.Errorf("Expected stable %s response of a stiff oscillator, got %f", method, output.SpectralAccelerations[1])
+		}
+	}
+	if periods[0] != 0 {
+		t.Errorf("Expected the periods to be left unchanged")
+	}
+
+	if _, err := CalcResponseSpectra(testAcceleration, 0.005, periods, 0.05, SpectraOptions{Method: "euler"}); err == nil {
+		t.Errorf("Expected error for unsupported method")
+	}
+	if _, err := CalcResponseSpectra(testAcceleration, 0.005, periods, 1, DefaultSpectraOptions()); err == nil {
+		t.Errorf("Expected error for critical damping")
+	}
+	if _, err := CalcResponseSpectra(testAcceleration, 0.005, periods, 0, SpectraOptions{Method: "frequency_domain"}); err == nil {
+		t.Errorf("Expected error for undamped frequency domain method")
+	}
+}
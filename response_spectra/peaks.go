@@ -0,0 +1,121 @@
+package response_spectra
+
+import "math"
+
+// SpectralPeaks holds the time, record sample and polarity of the peak response of each period.
+type SpectralPeaks struct {
+	Times   []float64
+	Indices []int
+	Signs   []int // +1 or -1
+}
+
+// CumulativePeaks holds the absolute peak responses up to each sample of the record, indexed by period and sample.
+type CumulativePeaks struct {
+	Times         []float64
+	Displacements [][]float64 // cm
+	Velocities    [][]float64 // cm/s
+	Accelerations [][]float64 // g
+}
+
+// peak is the response of largest absolute value, with its sign, and the integration step at which it occurs.
+type peak struct {
+	value float64
+	step  int
+}
+
+// responsePeaks tracks the peak relative displacement, relative velocity and absolute acceleration of an
+// oscillator, and optionally their running absolute peaks at each sample of the record. The integration steps
+// divide the time step of the record into stepsPerSample steps.
+type responsePeaks struct {
+	displacement, velocity, acceleration peak
+	stepsPerSample                       int
+	cumulative                           [3][]float64
+}
+
+func newResponsePeaks(numSamples int, cumulative bool) *responsePeaks {
+	peaks := responsePeaks{stepsPerSample: 1}
+	if cumulative {
+		for r := range peaks.cumulative {
+			peaks.cumulative[r] = make([]float64, numSamples)
+		}
+	}
+	return &peaks
+}
+
+// setTimeStep sets the integration time step h of the oscillator. It must divide the time step of the record dt.
+func (p *responsePeaks) setTimeStep(h, dt float64) {
+	p.stepsPerSample = int(math.Round(dt / h))
+}
+
+// record updates the peaks with the responses at the end of the integration step.
+func (p *responsePeaks) record(step int, xd, xv, xa float64) {
+	if math.Abs(xd) > math.Abs(p.displacement.value) {
+		p.displacement = peak{xd, step}
+	}
+	if math.Abs(xv) > math.Abs(p.velocity.value) {
+		p.velocity = peak{xv, step}
+	}
+	if math.Abs(xa) > math.Abs(p.acceleration.value) {
+		p.acceleration = peak{xa, step}
+	}
+	if p.cumulative[0] != nil && step%p.stepsPerSample == 0 {
+		sample := step / p.stepsPerSample
+		p.cumulative[0][sample] = math.Abs(p.displacement.value)
+		p.cumulative[1][sample] = math.Abs(p.velocity.value)
+		p.cumulative[2][sample] = math.Abs(p.acceleration.value)
+	}
+}
+
+// rigidPeaks returns the peaks of a rigid oscillator whose absolute acceleration is the ground acceleration.
+func rigidPeaks(accelerations []float64, cumulative bool) *responsePeaks {
+	peaks := newResponsePeaks(len(accelerations), cumulative)
+	for i, acc := range accelerations {
+		peaks.record(i, 0, 0, acc)
+	}
+	return peaks
+}
+
+// sample returns the time and nearest record sample of the integration step.
+func (p *responsePeaks) sample(step int, dt float64) (float64, int) {
+	return float64(step) * dt / float64(p.stepsPerSample), (step + p.stepsPerSample/2) / p.stepsPerSample
+}
+
+func newSpectralPeaks(n int) SpectralPeaks {
+	return SpectralPeaks{Times: make([]float64, n), Indices: make([]int, n), Signs: make([]int, n)}
+}
+
+func (s *SpectralPeaks) set(j int, p peak, peaks *responsePeaks, dt float64) {
+	s.Times[j], s.Indices[j] = peaks.sample(p.step, dt)
+	s.Signs[j] = 1
+	if p.value < 0 {
+		s.Signs[j] = -1
+	}
+}
+
+// newCumulativePeaks returns the running peaks of the oscillators in the units of the spectra, or nil if they were
+// not tracked.
+func newCumulativePeaks(peaks []*responsePeaks, dt float64) *CumulativePeaks {
+	if len(peaks) == 0 || peaks[0].cumulative[0] == nil {
+		return nil
+	}
+	numSamples := len(peaks[0].cumulative[0])
+	cumulative := CumulativePeaks{
+		Times:         make([]float64, numSamples),
+		Displacements: make([][]float64, len(peaks)),
+		Velocities:    make([][]float64, len(peaks)),
+		Accelerations: make([][]float64, len(peaks)),
+	}
+	for i := range cumulative.Times {
+		cumulative.Times[i] = float64(i) * dt
+	}
+	for j, p := range peaks {
+		cumulative.Displacements[j] = make([]float64, numSamples)
+		cumulative.Velocities[j] = make([]float64, numSamples)
+		for i := 0; i < numSamples; i++ {
+			cumulative.Displacements[j][i] = p.cumulative[0][i] * 981
+			cumulative.Velocities[j][i] = p.cumulative[1][i] * 981
+		}
+		cumulative.Accelerations[j] = p.cumulative[2]
+	}
+	return &cumulative
+}
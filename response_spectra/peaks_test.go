@@ -0,0 +1,69 @@
+package response_spectra
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+)
+
+func TestSpectralPeaks(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := []float64{0, 0.2, 1, 3}
+	spectra, err := CalcResponseSpectra(testAcceleration, 0.005, periods, 0.05, DefaultSpectraOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for j, period := range periods {
+		response, _ := CalcSDOFResponse(testAcceleration, 0.005, period, 0.05)
+		if spectra.AccelerationPeaks.Times[j] != response.PeakAccelerationTime ||
+			spectra.DisplacementPeaks.Times[j] != response.PeakDisplacementTime ||
+			spectra.VelocityPeaks.Times[j] != response.PeakVelocityTime {
+			t.Errorf("Expected times of peak of the response time histories at %.1f s", period)
+		}
+		index := spectra.DisplacementPeaks.Indices[j]
+		sign := float64(spectra.DisplacementPeaks.Signs[j])
+		if period > 0 && response.Displacements[index]*sign != spectra.SpectralDisplacements[j] {
+			t.Errorf("Expected signed peak displacement at sample %d", index)
+		}
+		index = spectra.AccelerationPeaks.Indices[j]
+		sign = float64(spectra.AccelerationPeaks.Signs[j])
+		if response.Accelerations[index]*sign != spectra.SpectralAccelerations[j] {
+			t.Errorf("Expected signed peak acceleration at sample %d", index)
+		}
+	}
+	if spectra.CumulativePeaks != nil {
+		t.Errorf("Expected no cumulative peaks by default")
+	}
+}
+
+func TestCumulativePeaks(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := []float64{0.1, 1}
+	for _, method := range []string{"nigam_jennings", "newmark_linear", "central_difference"} {
+		options := SpectraOptions{Method: method, CumulativePeaks: true}
+		spectra, err := CalcResponseSpectra(testAcceleration, 0.005, periods, 0.05, options)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		cumulative := spectra.CumulativePeaks
+		last := len(testAcceleration) - 1
+		for j := range periods {
+			if len(cumulative.Displacements[j]) != len(testAcceleration) {
+				t.Fatalf("Expected cumulative peaks at each sample")
+			}
+			if math.Abs(cumulative.Accelerations[j][last]-spectra.SpectralAccelerations[j]) > 1e-12 ||
+				math.Abs(cumulative.Displacements[j][last]-spectra.SpectralDisplacements[j]) > 1e-9 {
+				t.Errorf("Expected final cumulative peaks equal to the spectra with %s", method)
+			}
+			for i := 1; i <= last; i++ {
+				if cumulative.Velocities[j][i] < cumulative.Velocities[j][i-1] {
+					t.Fatalf("Expected non-decreasing cumulative peaks with %s", method)
+				}
+			}
+			if spectra.AccelerationPeaks.Indices[j] > last {
+				t.Errorf("Expected peak index within the record with %s", method)
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package response_spectra
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// nga21Periods are the periods of the NGA-West2 ground motion models, without PGA.
+var nga21Periods = []float64{
+	0.01, 0.02, 0.03, 0.05, 0.075, 0.1, 0.15, 0.2, 0.25, 0.3, 0.4, 0.5, 0.75, 1, 1.5, 2, 3, 4, 5, 7.5, 10,
+}
+
+// nga105Periods are the periods of the NGA-West2 flatfile spectra from 0.01 s to 10 s.
+var nga105Periods = []float64{
+	0.01, 0.02, 0.022, 0.025, 0.029, 0.03, 0.032, 0.035, 0.036, 0.04, 0.042, 0.044, 0.045, 0.046, 0.048, 0.05,
+	0.055, 0.06, 0.065, 0.067, 0.07, 0.075, 0.08, 0.085, 0.09, 0.095, 0.1, 0.11, 0.12, 0.13, 0.133, 0.14, 0.15,
+	0.16, 0.17, 0.18, 0.19, 0.2, 0.22, 0.24, 0.25, 0.26, 0.28, 0.29, 0.3, 0.32, 0.34, 0.35, 0.36, 0.38, 0.4, 0.42,
+	0.44, 0.45, 0.46, 0.48, 0.5, 0.55, 0.6, 0.65, 0.667, 0.7, 0.75, 0.8, 0.85, 0.9, 0.95, 1, 1.1, 1.2, 1.3, 1.4,
+	1.5, 1.6, 1.7, 1.8, 1.9, 2, 2.2, 2.4, 2.5, 2.6, 2.8, 3, 3.2, 3.4, 3.5, 3.6, 3.8, 4, 4.2, 4.4, 4.6, 4.8, 5, 5.5,
+	6, 6.5, 7, 7.5, 8, 8.5, 9, 9.5, 10,
+}
+
+// withPGA returns a copy of the periods, preceded by a zero period for the peak ground acceleration if pga is true.
+func withPGA(periods []float64, pga bool) []float64 {
+	if pga {
+		return append([]float64{0}, periods...)
+	}
+	return append([]float64{}, periods...)
+}
+
+// NGAWest2Periods returns the 21 periods of the NGA-West2 ground motion models from 0.01 s to 10 s.
+func NGAWest2Periods(pga bool) []float64 {
+	return withPGA(nga21Periods, pga)
+}
+
+// NGAWest2FlatfilePeriods returns the 105 periods of the NGA-West2 flatfile spectra from 0.01 s to 10 s.
+func NGAWest2FlatfilePeriods(pga bool) []float64 {
+	return withPGA(nga105Periods, pga)
+}
+
+// LogPeriods returns n logarithmically spaced periods from minPeriod to maxPeriod.
+func LogPeriods(minPeriod, maxPeriod float64, n int, pga bool) ([]float64, error) {
+	if minPeriod <= 0 || maxPeriod <= minPeriod {
+		return nil, errors.New("periods must be positive and increasing")
+	}
+	if n < 2 {
+		return nil, errors.New("at least two periods are required")
+	}
+	periods := make([]float64, n)
+	ratio := math.Log(maxPeriod / minPeriod)
+	for i := range periods {
+		periods[i] = minPeriod * math.Exp(ratio*float64(i)/float64(n-1))
+	}
+	periods[n-1] = maxPeriod
+	return withPGA(periods, pga), nil
+}
+
+// codePeriods returns the periods from 0 to maxPeriod with the given step, with the corner periods inserted.
+func codePeriods(periodStep, maxPeriod float64, corners ...float64) ([]float64, error) {
+	if periodStep <= 0 || maxPeriod <= periodStep {
+		return nil, errors.New("period step must be positive and smaller than the maximum period")
+	}
+	numSteps := int(math.Round(maxPeriod / periodStep))
+	periods := make([]float64, 0, numSteps+1+len(corners))
+	for i := 0; i <= numSteps; i++ {
+		// rounding removes the accumulated error of the step
+		periods = append(periods, math.Round(float64(i)*periodStep*1e9)/1e9)
+	}
+	for _, corner := range corners {
+		index := sort.SearchFloat64s(periods, corner)
+		if corner <= 0 || corner >= maxPeriod || (index < len(periods) && math.Abs(periods[index]-corner) < 1e-9) {
+			continue
+		}
+		periods = append(periods[:index], append([]float64{corner}, periods[index:]...)...)
+	}
+	return periods, nil
+}
+
+// TBDYPeriods returns the periods from 0 (PGA) to maxPeriod with the given step, including the corner periods
+// TA = 0.2 SD1/SDS, TB = SD1/SDS and TL = 6 s of the TBDY 2018 design spectrum.
+func TBDYPeriods(periodStep, maxPeriod, SDS, SD1 float64) ([]float64, error) {
+	if SDS <= 0 || SD1 <= 0 {
+		return nil, errors.New("spectral accelerations must be positive")
+	}
+	return codePeriods(periodStep, maxPeriod, 0.2*SD1/SDS, SD1/SDS, 6)
+}
+
+// EC8Periods returns the periods from 0 (PGA) to maxPeriod with the given step, including the corner periods TB,
+// TC and TD of the Eurocode 8 elastic spectrum.
+func EC8Periods(periodStep, maxPeriod, TB, TC, TD float64) ([]float64, error) {
+	if TB <= 0 || TC <= TB || TD <= TC {
+		return nil, errors.New("corner periods must be positive and increasing")
+	}
+	return codePeriods(periodStep, maxPeriod, TB, TC, TD)
+}
+
+// interpolate returns the values at the period by log-log interpolation between the bracketing periods. Intervals
+// starting at zero period, or with a non-positive value, are interpolated linearly.
+func interpolate(periods, values []float64, period float64, index int) float64 {
+	if period == periods[index] {
+		return values[index]
+	}
+	t1, t2 := periods[index-1], periods[index]
+	v1, v2 := values[index-1], values[index]
+	if t1 <= 0 || v1 <= 0 || v2 <= 0 {
+		return v1 + (v2-v1)*(period-t1)/(t2-t1)
+	}
+	fraction := math.Log(period/t1) / math.Log(t2/t1)
+	return math.Exp(math.Log(v1) + fraction*math.Log(v2/v1))
+}
+
+// InterpolateLogLog returns the values at the target periods by log-log interpolation. The periods must be
+// increasing and the targets within their range; a zero target is interpolated only if the periods include it.
+func InterpolateLogLog(periods, values, targets []float64) ([]float64, error) {
+	n := len(periods)
+	if n == 0 || len(values) != n {
+		return nil, errors.New("periods and values must have the same, non-zero length")
+	}
+	for j := 1; j < n; j++ {
+		if periods[j] <= periods[j-1] {
+			return nil, errors.New("periods must be increasing")
+		}
+	}
+	interpolated := make([]float64, len(targets))
+	for i, period := range targets {
+		if period < periods[0] || period > periods[n-1] {
+			return nil, errors.New("periods must be within the range of the spectra")
+		}
+		interpolated[i] = interpolate(periods, values, period, sort.SearchFloat64s(periods, period))
+	}
+	return interpolated, nil
+}
+
+// Interpolate returns the spectra at the periods by log-log interpolation. See InterpolateLogLog. The peak times,
+// signs and cumulative peaks are not interpolated.
+func (rsd *ResponseSpectraData) Interpolate(periods []float64) (*ResponseSpectraData, error) {
+	spectra := ResponseSpectraData{Periods: append([]float64{}, periods...)}
+	sources := [][]float64{
+		rsd.SpectralAccelerations, rsd.SpectralVelocities, rsd.SpectralDisplacements, rsd.PseudoAccelerations,
+		rsd.PseudoVelocities,
+	}
+	targets := []*[]float64{
+		&spectra.SpectralAccelerations, &spectra.SpectralVelocities, &spectra.SpectralDisplacements,
+		&spectra.PseudoAccelerations, &spectra.PseudoVelocities,
+	}
+	for r, source := range sources {
+		var err error
+		if *targets[r], err = InterpolateLogLog(rsd.Periods, source, periods); err != nil {
+			return nil, err
+		}
+	}
+	return &spectra, nil
+}
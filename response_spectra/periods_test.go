@@ -0,0 +1,105 @@
+package response_spectra
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+)
+
+func TestStandardPeriods(t *testing.T) {
+	if periods := NGAWest2Periods(true); len(periods) != 22 || periods[0] != 0 || periods[21] != 10 {
+		t.Errorf("Expected PGA and 21 NGA-West2 periods, got %v", periods)
+	}
+	periods := NGAWest2FlatfilePeriods(false)
+	if len(periods) != 105 {
+		t.Errorf("Expected 105 NGA-West2 flatfile periods, got %d", len(periods))
+	}
+	for j := 1; j < len(periods); j++ {
+		if periods[j] <= periods[j-1] {
+			t.Fatalf("Expected increasing periods at %f", periods[j])
+		}
+	}
+
+	logPeriods, err := LogPeriods(0.01, 10, 31, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if logPeriods[0] != 0.01 || logPeriods[30] != 10 || math.Abs(logPeriods[10]-0.1) > 1e-12 {
+		t.Errorf("Expected logarithmic spacing, got %v", logPeriods)
+	}
+	if _, err := LogPeriods(0, 10, 31, false); err == nil {
+		t.Errorf("Expected error for zero minimum period")
+	}
+}
+
+func TestCodePeriods(t *testing.T) {
+	periods, err := TBDYPeriods(0.1, 8, 1.2, 0.45)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if periods[0] != 0 || periods[len(periods)-1] != 8 || len(periods) != 83 {
+		t.Errorf("Expected 81 periods from 0 to 8 s with TA and TB, got %v", periods)
+	}
+	for _, corner := range []float64{0.075, 0.375, 6} {
+		found := false
+		for _, period := range periods {
+			found = found || math.Abs(period-corner) < 1e-12
+		}
+		if !found {
+			t.Errorf("Expected corner period %f", corner)
+		}
+	}
+	periods, _ = EC8Periods(0.05, 4, 0.15, 0.55, 2.5)
+	if len(periods) != 81 || periods[3] != 0.15 {
+		t.Errorf("Expected 81 periods from 0 to 4 s without duplicated corners, got %v", periods)
+	}
+	periods, _ = EC8Periods(0.05, 4, 0.12, 0.43, 2)
+	if len(periods) != 83 || periods[3] != 0.12 || periods[10] != 0.43 {
+		t.Errorf("Expected TB and TC inserted, got %v", periods)
+	}
+	if _, err := EC8Periods(0.05, 4, 0.5, 0.15, 2); err == nil {
+		t.Errorf("Expected error for decreasing corner periods")
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods, _ := LogPeriods(0.01, 5, 400, true)
+	spectra := ResponseSpectra(testAcceleration, 0.005, periods, 0.05)
+	if periods[0] != 0 {
+		t.Errorf("Expected periods not to be modified")
+	}
+	targets := NGAWest2Periods(true)[:20]
+	interpolated, err := spectra.Interpolate(targets)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := ResponseSpectra(testAcceleration, 0.005, targets, 0.05)
+	for j, period := range targets {
+		e := math.Abs(interpolated.PseudoAccelerations[j]-expected.PseudoAccelerations[j]) /
+			expected.PseudoAccelerations[j]
+		if e > 0.02 {
+			t.Errorf("Expected interpolated PSA within 2%% at %.3f s, got error %f", period, e)
+		}
+	}
+	if interpolated.SpectralAccelerations[0] != spectra.SpectralAccelerations[0] {
+		t.Errorf("Expected PGA at zero period")
+	}
+	if _, err := spectra.Interpolate([]float64{6}); err == nil {
+		t.Errorf("Expected error for extrapolation")
+	}
+}
+
+func TestInterpolateLogLog(t *testing.T) {
+	values, err := InterpolateLogLog([]float64{0, 1, 4}, []float64{1, 2, 8}, []float64{0.5, 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if math.Abs(values[0]-1.5) > 1e-12 || math.Abs(values[1]-4) > 1e-12 {
+		t.Errorf("Expected linear interpolation from zero period and log-log beyond, got %v", values)
+	}
+	if _, err := InterpolateLogLog([]float64{1, 1}, []float64{1, 2}, []float64{1}); err == nil {
+		t.Errorf("Expected error for repeated periods")
+	}
+}
@@ -1,8 +1,10 @@
 package response_spectra
 
 import (
-	np "github.com/geoport/numpy4go/vectors"
 	"math"
+
+	"github.com/geoport/GoQuakeLib/internal/numeric"
+	np "github.com/geoport/numpy4go/vectors"
 )
 
 type ResponseSpectraData struct {
@@ -12,8 +14,46 @@ type ResponseSpectraData struct {
 	PseudoAccelerations   []float64
 	PseudoVelocities      []float64
 	Periods               []float64
+	DisplacementPeaks     SpectralPeaks
+	VelocityPeaks         SpectralPeaks
+	AccelerationPeaks     SpectralPeaks
+	CumulativePeaks       *CumulativePeaks // running peaks over time, if requested in the options
+}
+
+// sdofConstants holds the Nigam-Jennings recurrence coefficients of a single period.
+type sdofConstants struct {
+	f1, f2, f4, f5, f6, g1, g2, h1, h2, omega2 float64
+}
+
+func periodConstants(constants map[string][]float64, omega2 []float64, j int) sdofConstants {
+	return sdofConstants{
+		f1: constants["f1"][j], f2: constants["f2"][j], f4: constants["f4"][j], f5: constants["f5"][j],
+		f6: constants["f6"][j], g1: constants["g1"][j], g2: constants["g2"][j], h1: constants["h1"][j],
+		h2: constants["h2"][j], omega2: omega2[j],
+	}
 }
 
+// step returns the relative displacement, velocity and absolute acceleration at the end of a time step from the
+// ground accelerations at both ends of the step and the displacement and velocity at its start. The explicit
+// float64 conversions keep the products from being fused so that the results do not depend on the platform.
+func (c *sdofConstants) step(acc, nextAcc, xd, xv, dt float64) (float64, float64, float64) {
+	dug := nextAcc - acc
+	z1 := c.f2 * dug
+	z2 := c.f2 * acc
+	z3 := c.f1 * dug
+	z4 := z1 * (1 / dt)
+	b := xd + (z2 - z3)
+	a := float64(c.f5*b) + float64(c.f4*z4) + float64(c.f4*xv)
+	z321 := z3 - (z1 + z2)
+	xd = float64(a*c.g1) + (float64(b*c.g2) + z321)
+	xv = float64(a*c.h1) - (float64(b*c.h2) + z4)
+	return xd, xv, -(float64(c.f6*xv) + float64(c.omega2*xd))
+}
+
+// GetTimeSeries returns the "xd", "xv" and "xa" responses, indexed by time step and period, from the second sample
+// on.
+//
+// Deprecated: use CalcSDOFResponse or CalcSDOFResponses, which do not need the recurrence constants.
 func GetTimeSeries(
 	constants map[string][]float64, omega2 []float64, numSteps, numPeriods int, accelerations []float64, dt float64,
 ) map[string][][]float64 {
@@ -21,38 +61,13 @@ func GetTimeSeries(
 	xv := np.Zeros(numSteps-1, numPeriods)
 	xa := np.Zeros(numSteps-1, numPeriods)
 
-	for i := 0; i < numSteps-1; i++ {
-		dug := accelerations[i+1] - accelerations[i]
-		z1 := np.MultiplyBy(constants["f2"], dug)
-		z2 := np.MultiplyBy(constants["f2"], accelerations[i])
-		z3 := np.MultiplyBy(constants["f1"], dug)
-		z4 := np.MultiplyBy(z1, 1/dt)
-
-		var a, b []float64
-		if i == 0 {
-			b = np.SumWith(z2, np.MultiplyBy(z3, -1))
-			a = np.SumWith(np.MultiplyBy(constants["f5"], b), np.MultiplyBy(constants["f4"], z4))
-		} else {
-			b = np.SumWith(xd[i-1], np.SumWith(z2, np.MultiplyBy(z3, -1)))
-			a = np.SumWith(
-				np.SumWith(
-					np.MultiplyBy(constants["f5"], b), np.MultiplyBy(constants["f4"], z4),
-				),
-				np.MultiplyBy(constants["f4"], xv[i-1]),
-			)
+	for j := 0; j < numPeriods; j++ {
+		c := periodConstants(constants, omega2, j)
+		var d, v float64
+		for i := 0; i < numSteps-1; i++ {
+			d, v, xa[i][j] = c.step(accelerations[i], accelerations[i+1], d, v, dt)
+			xd[i][j], xv[i][j] = d, v
 		}
-
-		z321 := np.SumWith(z3, np.MultiplyBy(np.SumWith(z1, z2), -1))
-		ag1 := np.MultiplyBy(a, constants["g1"])
-		ah1 := np.MultiplyBy(a, constants["h1"])
-		bg2 := np.MultiplyBy(b, constants["g2"])
-		bh2 := np.MultiplyBy(b, constants["h2"])
-
-		xd[i] = np.SumWith(ag1, np.SumWith(bg2, z321))
-		xv[i] = np.SumWith(ah1, np.MultiplyBy(np.SumWith(bh2, z4), -1))
-		o2xd := np.MultiplyBy(omega2, xd[i])
-		f6xv := np.MultiplyBy(constants["f6"], xv[i])
-		xa[i] = np.MultiplyBy(np.SumWith(f6xv, o2xd), -1)
 	}
 	timeSeriesData := map[string][][]float64{
 		"xd": xd,
@@ -62,10 +77,42 @@ func GetTimeSeries(
 	return timeSeriesData
 }
 
-func ResponseSpectra(accelerations []float64, dt float64, periods []float64, damping float64) *ResponseSpectraData {
-	if periods[0] == 0 {
-		periods[0] = 1e-6
+// peaks records the relative displacement, relative velocity and absolute acceleration of the oscillator.
+func (c *sdofConstants) peaks(accelerations []float64, dt float64, peaks *responsePeaks) {
+	var xd, xv, xa float64
+	for i := 0; i < len(accelerations)-1; i++ {
+		xd, xv, xa = c.step(accelerations[i], accelerations[i+1], xd, xv, dt)
+		peaks.record(i+1, xd, xv, xa)
 	}
+}
+
+// peakResponses returns the peak relative displacement, relative velocity and absolute acceleration of each period
+// computed with the Nigam-Jennings recurrence.
+func peakResponses(
+	constants map[string][]float64, omega2 []float64, accelerations []float64, dt float64,
+) []*responsePeaks {
+	return parallelPeaks(len(omega2), func(j int) *responsePeaks {
+		c := periodConstants(constants, omega2, j)
+		peaks := newResponsePeaks(len(accelerations), false)
+		c.peaks(accelerations, dt, peaks)
+		return peaks
+	})
+}
+
+// parallelPeaks evaluates the peak responses of each period on a pool of workers. Only the peaks are stored.
+func parallelPeaks(numPeriods int, peaks func(j int) *responsePeaks) []*responsePeaks {
+	results := make([]*responsePeaks, numPeriods)
+	numeric.ParallelFor(numPeriods, func(j int) {
+		results[j] = peaks(j)
+	})
+	return results
+}
+
+// nigamJenningsConstants returns the recurrence coefficients of each period with the circular frequencies and
+// their squares.
+func nigamJenningsConstants(
+	dt float64, periods []float64, damping float64,
+) (map[string][]float64, []float64, []float64) {
 	omega := np.DividedBy(np.Repeat(2*math.Pi, len(periods)), periods)
 	omega2 := np.Pow(omega, 2)
 	omega3 := np.Pow(omega, 3)
@@ -89,18 +136,56 @@ func ResponseSpectra(accelerations []float64, dt float64, periods []float64, dam
 	f3g2 := np.MultiplyBy(constants["f3"], constants["g2"])
 	constants["h1"] = np.SumWith(oDg2, np.MultiplyBy(f3g1, -1))
 	constants["h2"] = np.SumWith(oDg1, f3g2)
+	return constants, omega, omega2
+}
 
-	timeSeriesData := GetTimeSeries(constants, omega2, len(accelerations), len(periods), accelerations, dt)
+// ResponseSpectra returns the response spectra computed with the Nigam-Jennings recurrence. The periods are not
+// modified and a zero period gives the peak ground acceleration. It panics on invalid input; CalcResponseSpectra
+// returns an error instead.
+func ResponseSpectra(accelerations []float64, dt float64, periods []float64, damping float64) *ResponseSpectraData {
+	if damping < 0 || damping >= 1 {
+		panic("damping ratio must be in [0, 1)")
+	}
+	periods = append([]float64{}, periods...)
+	spectra, err := CalcResponseSpectra(accelerations, dt, periods, damping, DefaultSpectraOptions())
+	if err != nil {
+		panic(err)
+	}
+	return spectra
+}
+
+// spectraFromPeaks returns the spectra of the peak responses of the oscillators, in g and g*s2 units, with the
+// times and signs of the peaks. The pseudo spectra of a zero period are those of a rigid oscillator.
+func spectraFromPeaks(periods []float64, peaks []*responsePeaks, dt float64) *ResponseSpectraData {
+	n := len(periods)
 	var spectraData = ResponseSpectraData{
 		Periods:               periods,
-		SpectralAccelerations: np.Max2D(np.Abs2D(timeSeriesData["xa"]), 0),                     // g
-		SpectralVelocities:    np.MultiplyBy(np.Max2D(np.Abs2D(timeSeriesData["xv"]), 0), 981), // cm/s
-		SpectralDisplacements: np.MultiplyBy(np.Max2D(np.Abs2D(timeSeriesData["xd"]), 0), 981), // cm
+		SpectralAccelerations: make([]float64, n), // g
+		SpectralVelocities:    make([]float64, n), // cm/s
+		SpectralDisplacements: make([]float64, n), // cm
+		PseudoVelocities:      make([]float64, n),
+		PseudoAccelerations:   make([]float64, n),
+		DisplacementPeaks:     newSpectralPeaks(n),
+		VelocityPeaks:         newSpectralPeaks(n),
+		AccelerationPeaks:     newSpectralPeaks(n),
+		CumulativePeaks:       newCumulativePeaks(peaks, dt),
+	}
+	for j, p := range peaks {
+		spectraData.SpectralAccelerations[j] = math.Abs(p.acceleration.value)
+		spectraData.SpectralVelocities[j] = math.Abs(p.velocity.value) * 981
+		spectraData.SpectralDisplacements[j] = math.Abs(p.displacement.value) * 981
+		spectraData.DisplacementPeaks.set(j, p.displacement, p, dt)
+		spectraData.VelocityPeaks.set(j, p.velocity, p, dt)
+		spectraData.AccelerationPeaks.set(j, p.acceleration, p, dt)
+	}
+	for j, period := range periods {
+		if period == 0 {
+			spectraData.PseudoAccelerations[j] = spectraData.SpectralAccelerations[j]
+			continue
+		}
+		omega := 2 * math.Pi / period
+		spectraData.PseudoVelocities[j] = omega * spectraData.SpectralDisplacements[j]                               // cm/s
+		spectraData.PseudoAccelerations[j] = math.Pow(omega, 2) * spectraData.SpectralDisplacements[j] * (1.0 / 981) // g
 	}
-	spectraData.PseudoVelocities = np.MultiplyBy(omega, spectraData.SpectralDisplacements) // cm/s
-	spectraData.PseudoAccelerations = np.MultiplyBy(
-		np.MultiplyBy(omega2, spectraData.SpectralDisplacements), 1.0/981,
-	) // g
-
 	return &spectraData
 }
@@ -2,6 +2,7 @@ package response_spectra
 
 import (
 	td "github.com/geoport/GoQuakeLib/TestData"
+	"math"
 	"reflect"
 	"testing"
 
@@ -79,3 +80,37 @@ func TestCalcResponseSpectra(t *testing.T) {
 		t.Errorf("pseudo_velocity Expected %v, got %v", expectedPseudoVel, outputPseudoVel)
 	}
 }
+
+func TestPeakResponses(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	timeSeries := GetTimeSeries(
+		td.TestConst, td.TestOmega2, len(testAcceleration), len(td.TestPeriods), testAcceleration, 0.005,
+	)
+	peaks := peakResponses(td.TestConst, td.TestOmega2, testAcceleration, 0.005)
+	peakXd := make([]float64, len(peaks))
+	peakXv := make([]float64, len(peaks))
+	peakXa := make([]float64, len(peaks))
+	for j, p := range peaks {
+		peakXd[j], peakXv[j], peakXa[j] = math.Abs(p.displacement.value), math.Abs(p.velocity.value),
+			math.Abs(p.acceleration.value)
+	}
+
+	if !reflect.DeepEqual(np.Max2D(np.Abs2D(timeSeries["xd"]), 0), peakXd) {
+		t.Errorf("Expected peak displacements equal to the maxima of the time series")
+	}
+	if !reflect.DeepEqual(np.Max2D(np.Abs2D(timeSeries["xv"]), 0), peakXv) {
+		t.Errorf("Expected peak velocities equal to the maxima of the time series")
+	}
+	if !reflect.DeepEqual(np.Max2D(np.Abs2D(timeSeries["xa"]), 0), peakXa) {
+		t.Errorf("Expected peak accelerations equal to the maxima of the time series")
+	}
+}
+
+func BenchmarkResponseSpectra(b *testing.B) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := np.Arange(0.01, 5, 0.01)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ResponseSpectra(testAcceleration, 0.005, periods, 0.05)
+	}
+}
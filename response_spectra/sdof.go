@@ -0,0 +1,99 @@
+package response_spectra
+
+import (
+	"errors"
+	"math"
+
+	"github.com/geoport/GoQuakeLib/internal/numeric"
+)
+
+// SDOFResponse holds the response time histories of a linear oscillator with their absolute peaks and the times at
+// which they occur.
+type SDOFResponse struct {
+	Period               float64
+	Damping              float64
+	Times                []float64
+	Displacements        []float64 // relative displacement (cm)
+	Velocities           []float64 // relative velocity (cm/s)
+	Accelerations        []float64 // absolute acceleration (g)
+	PeakDisplacement     float64
+	PeakDisplacementTime float64
+	PeakVelocity         float64
+	PeakVelocityTime     float64
+	PeakAcceleration     float64
+	PeakAccelerationTime float64
+}
+
+// CalcSDOFResponse returns the response time histories of the oscillator with the given period and damping ratio to
+// the ground accelerations (g), computed with the Nigam-Jennings recurrence. The oscillator is at rest at the first
+// sample. A zero period is a rigid oscillator whose absolute acceleration is the ground acceleration.
+func CalcSDOFResponse(accelerations []float64, dt, period, damping float64) (*SDOFResponse, error) {
+	if err := checkSpectraInput(accelerations, dt, []float64{period}, damping); err != nil {
+		return nil, err
+	}
+	n := len(accelerations)
+	response := SDOFResponse{
+		Period:        period,
+		Damping:       damping,
+		Times:         make([]float64, n),
+		Displacements: make([]float64, n),
+		Velocities:    make([]float64, n),
+		Accelerations: make([]float64, n),
+	}
+	for i := range response.Times {
+		response.Times[i] = float64(i) * dt
+	}
+	if period == 0 {
+		copy(response.Accelerations, accelerations)
+	} else {
+		constants, _, omega2 := nigamJenningsConstants(dt, []float64{period}, damping)
+		c := periodConstants(constants, omega2, 0)
+		var xd, xv, xa float64
+		for i := 0; i < n-1; i++ {
+			xd, xv, xa = c.step(accelerations[i], accelerations[i+1], xd, xv, dt)
+			response.Displacements[i+1] = xd * 981
+			response.Velocities[i+1] = xv * 981
+			response.Accelerations[i+1] = xa
+		}
+	}
+	response.PeakDisplacement, response.PeakDisplacementTime = absolutePeak(response.Displacements, dt)
+	response.PeakVelocity, response.PeakVelocityTime = absolutePeak(response.Velocities, dt)
+	response.PeakAcceleration, response.PeakAccelerationTime = absolutePeak(response.Accelerations, dt)
+	return &response, nil
+}
+
+// CalcSDOFResponses returns the response time histories of each damping ratio and period, indexed by damping
+// first. See CalcSDOFResponse.
+func CalcSDOFResponses(accelerations []float64, dt float64, periods, dampings []float64) ([][]*SDOFResponse, error) {
+	if len(periods) == 0 || len(dampings) == 0 {
+		return nil, errors.New("periods and damping ratios must not be empty")
+	}
+	for _, damping := range dampings {
+		if err := checkSpectraInput(accelerations, dt, periods, damping); err != nil {
+			return nil, err
+		}
+	}
+	numPeriods := len(periods)
+	responses := make([][]*SDOFResponse, len(dampings))
+	for d := range responses {
+		responses[d] = make([]*SDOFResponse, numPeriods)
+	}
+	numeric.ParallelFor(numPeriods*len(dampings), func(index int) {
+		d, j := index/numPeriods, index%numPeriods
+		// the inputs are checked above
+		responses[d][j], _ = CalcSDOFResponse(accelerations, dt, periods[j], dampings[d])
+	})
+	return responses, nil
+}
+
+// absolutePeak returns the largest absolute value of the time history and its time.
+func absolutePeak(values []float64, dt float64) (float64, float64) {
+	var peak float64
+	var index int
+	for i, value := range values {
+		if math.Abs(value) > peak {
+			peak, index = math.Abs(value), i
+		}
+	}
+	return peak, float64(index) * dt
+}
@@ -0,0 +1,59 @@
+package response_spectra
+
+import (
+	"math"
+	"testing"
+
+	td "github.com/geoport/GoQuakeLib/TestData"
+)
+
+func TestCalcSDOFResponse(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	periods := []float64{0.2, 1}
+	spectra := ResponseSpectra(testAcceleration, 0.005, append([]float64{}, periods...), 0.05)
+	for j, period := range periods {
+		response, err := CalcSDOFResponse(testAcceleration, 0.005, period, 0.05)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(response.Times) != len(testAcceleration) || response.Displacements[0] != 0 {
+			t.Fatalf("Expected responses at each sample starting from rest")
+		}
+		if response.PeakDisplacement != spectra.SpectralDisplacements[j] ||
+			response.PeakVelocity != spectra.SpectralVelocities[j] ||
+			response.PeakAcceleration != spectra.SpectralAccelerations[j] {
+			t.Errorf("Expected peaks of the response spectra at %.1f s", period)
+		}
+		index := int(math.Round(response.PeakAccelerationTime / 0.005))
+		if math.Abs(response.Accelerations[index]) != response.PeakAcceleration {
+			t.Errorf("Expected peak acceleration at %f s", response.PeakAccelerationTime)
+		}
+	}
+
+	rigid, _ := CalcSDOFResponse(testAcceleration, 0.005, 0, 0.05)
+	for i, acc := range testAcceleration {
+		if rigid.Accelerations[i] != acc {
+			t.Fatalf("Expected ground acceleration of a rigid oscillator")
+		}
+	}
+	if _, err := CalcSDOFResponse(testAcceleration, 0.005, 1, 1); err == nil {
+		t.Errorf("Expected error for damping of 1")
+	}
+}
+
+func TestCalcSDOFResponses(t *testing.T) {
+	testAcceleration := td.TestMotion["Accelerations"].([]float64)
+	responses, err := CalcSDOFResponses(testAcceleration, 0.005, []float64{0.5, 1, 2}, []float64{0.02, 0.05})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(responses) != 2 || len(responses[1]) != 3 {
+		t.Fatalf("Expected responses indexed by damping and period")
+	}
+	if responses[1][2].Period != 2 || responses[1][2].Damping != 0.05 {
+		t.Errorf("Expected period 2 and damping 0.05, got %f and %f", responses[1][2].Period, responses[1][2].Damping)
+	}
+	if responses[0][0].PeakDisplacement <= responses[1][0].PeakDisplacement {
+		t.Errorf("Expected larger displacement with lower damping")
+	}
+}
@@ -0,0 +1,189 @@
+package strength_reduction
+
+import (
+	"errors"
+	"math"
+
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+)
+
+// Model returns the strength reduction factor R of an oscillator of the given period for a ductility demand mu.
+type Model func(mu, period float64) (float64, error)
+
+// Comparison holds the reduction factors of a model against those derived from records.
+type Comparison struct {
+	Periods       []float64
+	Ductilities   []float64
+	RecordFactors []float64
+	ModelFactors  []float64
+	Ratios        []float64 // model / record
+	Bias          float64   // geometric mean of the ratios
+	LogStd        float64   // standard deviation of the natural logarithm of the ratios
+}
+
+func checkInput(mu, period float64) error {
+	if mu < 1 {
+		return errors.New("ductility must be at least 1")
+	}
+	if period <= 0 {
+		return errors.New("period must be positive")
+	}
+	return nil
+}
+
+// NewmarkHall returns the reduction factor of Newmark and Hall (1982): 1 below 1/33 s, sqrt(2mu-1) between 0.125 s
+// and Tc*sqrt(2mu-1)/mu, equal displacements (R = mu) above the corner period Tc of the spectrum, with log-linear
+// transitions between these ranges.
+func NewmarkHall(mu, period, cornerPeriod float64) (float64, error) {
+	if err := checkInput(mu, period); err != nil {
+		return 0, err
+	}
+	if cornerPeriod <= 0.125 {
+		return 0, errors.New("corner period must be longer than 0.125 s")
+	}
+	const ta, tb = 1. / 33, 0.125
+	equalEnergy := math.Sqrt(2*mu - 1)
+	tc1 := cornerPeriod * equalEnergy / mu
+	switch {
+	case period <= ta:
+		return 1, nil
+	case period <= tb:
+		beta := math.Log(period/ta) / (2 * math.Log(tb/ta))
+		return math.Pow(2*mu-1, beta), nil
+	case period <= tc1:
+		return equalEnergy, nil
+	case period <= cornerPeriod:
+		return period * mu / cornerPeriod, nil
+	default:
+		return mu, nil
+	}
+}
+
+// NassarKrawinkler returns the reduction factor of Nassar and Krawinkler (1991), R = (c(mu-1) + 1)^(1/c) with
+// c = T^a / (1 + T^a) + b / T. The coefficients a and b are given for post-yield stiffness ratios of 0, 0.02 and
+// 0.10 only.
+func NassarKrawinkler(mu, period, hardeningRatio float64) (float64, error) {
+	if err := checkInput(mu, period); err != nil {
+		return 0, err
+	}
+	var a, b float64
+	switch hardeningRatio {
+	case 0:
+		a, b = 1, 0.42
+	case 0.02:
+		a, b = 1, 0.37
+	case 0.1:
+		a, b = 0.8, 0.29
+	default:
+		return 0, errors.New("hardening ratio must be 0, 0.02 or 0.10")
+	}
+	c := math.Pow(period, a)/(1+math.Pow(period, a)) + b/period
+	return math.Pow(c*(mu-1)+1, 1/c), nil
+}
+
+// MirandaBertero returns the reduction factor of Miranda and Bertero (1994), R = (mu-1)/phi + 1 >= 1, for "rock",
+// "alluvium" or "soft_soil" sites. The predominant period of the ground motion is used by "soft_soil" only.
+func MirandaBertero(mu, period float64, site string, groundPeriod float64) (float64, error) {
+	if err := checkInput(mu, period); err != nil {
+		return 0, err
+	}
+	var phi float64
+	switch site {
+	case "rock":
+		phi = 1 + 1/(10*period-mu*period) - math.Exp(-1.5*math.Pow(math.Log(period)-0.6, 2))/(2*period)
+	case "alluvium":
+		phi = 1 + 1/(12*period-mu*period) - 2*math.Exp(-2*math.Pow(math.Log(period)-0.2, 2))/(5*period)
+	case "soft_soil":
+		if groundPeriod <= 0 {
+			return 0, errors.New("predominant ground period must be positive")
+		}
+		ratio := groundPeriod / period
+		phi = 1 + ratio/3 - 3*ratio/4*math.Exp(-3*math.Pow(math.Log(period/groundPeriod)-0.25, 2))
+	default:
+		return 0, errors.New("site class not supported")
+	}
+	return math.Max((mu-1)/phi+1, 1), nil
+}
+
+// VidicFajfar returns the reduction factor of Vidic, Fajfar and Fischinger (1994) with the coefficients c1 = 1.0,
+// c2 = 0.65, cR = 1.0 and cT = 0.30 of bilinear oscillators with mass proportional damping:
+// R = c1 (mu-1)^cR T/T0 + 1 below T0 = c2 mu^cT Tc and c1 (mu-1)^cR + 1 above it.
+func VidicFajfar(mu, period, cornerPeriod float64) (float64, error) {
+	if err := checkInput(mu, period); err != nil {
+		return 0, err
+	}
+	if cornerPeriod <= 0 {
+		return 0, errors.New("corner period must be positive")
+	}
+	const c1, c2, cR, cT = 1.0, 0.65, 1.0, 0.30
+	t0 := c2 * math.Pow(mu, cT) * cornerPeriod
+	r := c1 * math.Pow(mu-1, cR)
+	if period <= t0 {
+		return r*period/t0 + 1, nil
+	}
+	return r + 1, nil
+}
+
+// DuctilityDemand inverts the model to return the ductility at which it gives the reduction factor, searching
+// ductilities between 1 and maxDuctility by bisection. The models increase monotonically with ductility.
+func DuctilityDemand(model Model, reductionFactor, period, maxDuctility float64) (float64, error) {
+	if reductionFactor < 1 {
+		return 0, errors.New("reduction factor must be at least 1")
+	}
+	lower, upper := 1., maxDuctility
+	rUpper, err := model(upper, period)
+	if err != nil {
+		return 0, err
+	}
+	if rUpper < reductionFactor {
+		return 0, errors.New("reduction factor is not reached below the maximum ductility")
+	}
+	for upper-lower > 1e-8*upper {
+		middle := (lower + upper) / 2
+		r, err := model(middle, period)
+		if err != nil {
+			return 0, err
+		}
+		if r < reductionFactor {
+			lower = middle
+		} else {
+			upper = middle
+		}
+	}
+	return (lower + upper) / 2, nil
+}
+
+// Compare evaluates the model at the periods and ductilities of inelastic spectra derived from records and returns
+// the ratios of the model to the record reduction factors.
+func Compare(model Model, spectra *rs.InelasticSpectraData) (*Comparison, error) {
+	if len(spectra.Periods) == 0 {
+		return nil, errors.New("spectra are empty")
+	}
+	n := len(spectra.Periods)
+	comparison := Comparison{
+		Periods:       spectra.Periods,
+		Ductilities:   spectra.Ductilities,
+		RecordFactors: spectra.ReductionFactors,
+		ModelFactors:  make([]float64, n),
+		Ratios:        make([]float64, n),
+	}
+	var sum, sumSquares float64
+	for j, period := range spectra.Periods {
+		// a ductility marginally below 1 is an elastic oscillator
+		r, err := model(math.Max(spectra.Ductilities[j], 1), period)
+		if err != nil {
+			return nil, err
+		}
+		comparison.ModelFactors[j] = r
+		comparison.Ratios[j] = r / spectra.ReductionFactors[j]
+		logRatio := math.Log(comparison.Ratios[j])
+		sum += logRatio
+		sumSquares += logRatio * logRatio
+	}
+	mean := sum / float64(n)
+	comparison.Bias = math.Exp(mean)
+	if n > 1 {
+		comparison.LogStd = math.Sqrt(math.Max((sumSquares-float64(n)*mean*mean)/float64(n-1), 0))
+	}
+	return &comparison, nil
+}
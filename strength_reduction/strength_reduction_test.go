@@ -0,0 +1,113 @@
+package strength_reduction
+
+import (
+	"math"
+	"testing"
+
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+)
+
+func TestNewmarkHall(t *testing.T) {
+	cases := []struct{ period, expected float64 }{
+		{0.02, 1},
+		{0.2, math.Sqrt(7)},
+		{0.4, 0.4 * 4 / 0.5},
+		{2, 4},
+	}
+	for _, c := range cases {
+		r, err := NewmarkHall(4, c.period, 0.5)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if math.Abs(r-c.expected) > 1e-12 {
+			t.Errorf("Expected R = %v at T = %v, got %v", c.expected, c.period, r)
+		}
+	}
+	if _, err := NewmarkHall(0.5, 1, 0.5); err == nil {
+		t.Errorf("Expected error for ductility below 1")
+	}
+}
+
+func TestNassarKrawinkler(t *testing.T) {
+	r, err := NassarKrawinkler(3, 1, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := math.Pow(0.92*2+1, 1/0.92)
+	if math.Abs(r-expected) > 1e-12 {
+		t.Errorf("Expected R = %v, got %v", expected, r)
+	}
+	if r, _ := NassarKrawinkler(1, 0.5, 0.1); math.Abs(r-1) > 1e-12 {
+		t.Errorf("Expected R = 1 for an elastic oscillator, got %v", r)
+	}
+	if _, err := NassarKrawinkler(3, 1, 0.05); err == nil {
+		t.Errorf("Expected error for an untabulated hardening ratio")
+	}
+}
+
+func TestMirandaBertero(t *testing.T) {
+	for _, site := range []string{"rock", "alluvium", "soft_soil"} {
+		r, err := MirandaBertero(4, 3, site, 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		// the equal displacement rule holds approximately at long periods
+		if math.Abs(r-4)/4 > 0.15 {
+			t.Errorf("Expected R close to the ductility at long periods on %s, got %v", site, r)
+		}
+	}
+	if _, err := MirandaBertero(4, 1, "soft_soil", 0); err == nil {
+		t.Errorf("Expected error for a soft soil without ground period")
+	}
+	if _, err := MirandaBertero(4, 1, "clay", 1); err == nil {
+		t.Errorf("Expected error for an unsupported site class")
+	}
+}
+
+func TestVidicFajfar(t *testing.T) {
+	t0 := 0.65 * math.Pow(3, 0.3) * 0.5
+	if r, _ := VidicFajfar(3, t0/2, 0.5); math.Abs(r-2) > 1e-12 {
+		t.Errorf("Expected R = 2 at half of T0, got %v", r)
+	}
+	if r, _ := VidicFajfar(3, 2, 0.5); math.Abs(r-3) > 1e-12 {
+		t.Errorf("Expected R = 3 above T0, got %v", r)
+	}
+}
+
+func TestDuctilityDemand(t *testing.T) {
+	model := func(mu, period float64) (float64, error) { return NassarKrawinkler(mu, period, 0.02) }
+	r, _ := model(5, 0.3)
+	mu, err := DuctilityDemand(model, r, 0.3, 50)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if math.Abs(mu-5) > 1e-6 {
+		t.Errorf("Expected ductility 5, got %v", mu)
+	}
+	if _, err := DuctilityDemand(model, 100, 0.3, 2); err == nil {
+		t.Errorf("Expected error for an unreachable reduction factor")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	model := func(mu, period float64) (float64, error) { return VidicFajfar(mu, period, 0.5) }
+	spectra := rs.InelasticSpectraData{
+		Periods:          []float64{0.1, 0.5, 1, 2},
+		Ductilities:      []float64{2, 3, 4, 0.999},
+		ReductionFactors: make([]float64, 4),
+	}
+	for j, period := range spectra.Periods {
+		r, _ := model(math.Max(spectra.Ductilities[j], 1), period)
+		spectra.ReductionFactors[j] = 2 * r
+	}
+	comparison, err := Compare(model, &spectra)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if math.Abs(comparison.Bias-0.5) > 1e-12 || comparison.LogStd > 1e-12 {
+		t.Errorf("Expected bias 0.5 without dispersion, got %v and %v", comparison.Bias, comparison.LogStd)
+	}
+	if _, err := Compare(model, &rs.InelasticSpectraData{}); err == nil {
+		t.Errorf("Expected error for empty spectra")
+	}
+}
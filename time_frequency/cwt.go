@@ -0,0 +1,269 @@
+package time_frequency
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+
+	"github.com/geoport/GoQuakeLib/fft"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// CWTOptions controls the wavelet and the scales of the continuous wavelet transform.
+// Scales are s0 * 2^(j*ScaleSpacing) for j = 0..NumScales-1 (Torrence & Compo, 1998).
+type CWTOptions struct {
+	Wavelet      string  // "morlet", "mexican_hat" or "dog" (derivative of Gaussian)
+	Order        int     // order of the "dog" wavelet, the Morlet nondimensional frequency if "morlet" (6 if zero)
+	MinScale     float64 // smallest scale s0 in seconds, 0 uses twice the time step
+	ScaleSpacing float64 // spacing between scales in octaves, 0 uses 0.125
+	NumScales    int     // number of scales, 0 covers the record length
+}
+
+// Scalogram holds the continuous wavelet transform of a record.
+type Scalogram struct {
+	Times        []float64
+	Scales       []float64      // wavelet scales in seconds
+	Periods      []float64      // equivalent Fourier periods of the scales in seconds
+	Coefficients [][]complex128 // wavelet coefficients, [scale][time]
+	Power        [][]float64    // wavelet power |W|^2, [scale][time]
+	TimeStep     float64
+	ScaleSpacing float64
+	wavelet      wavelet
+	mean         float64
+	cDelta       float64
+}
+
+// wavelet describes a mother wavelet by its Fourier transform.
+type wavelet struct {
+	fourier      func(scaledOmega float64) complex128 // Fourier transform of the mother wavelet at s*omega
+	periodFactor float64                              // Fourier period / scale
+	valueAtZero  float64                              // psi(0) used by the reconstruction
+}
+
+func getWavelet(name string, order int) (wavelet, error) {
+	switch name {
+	case "morlet":
+		omega0 := 6.
+		if order != 0 {
+			omega0 = float64(order)
+		}
+		return wavelet{
+			fourier: func(w float64) complex128 {
+				if w <= 0 {
+					return 0
+				}
+				return complex(math.Pow(math.Pi, -0.25)*math.Exp(-(w-omega0)*(w-omega0)/2), 0)
+			},
+			periodFactor: 4 * math.Pi / (omega0 + math.Sqrt(2+omega0*omega0)),
+			valueAtZero:  math.Pow(math.Pi, -0.25),
+		}, nil
+	case "mexican_hat", "dog":
+		m := order
+		if name == "mexican_hat" {
+			m = 2
+		}
+		if m < 1 {
+			return wavelet{}, errors.New("order of the derivative of Gaussian wavelet must be a positive integer")
+		}
+		norm := 1 / math.Sqrt(math.Gamma(float64(m)+0.5))
+		coefficient := -cmplx.Pow(complex(0, 1), complex(float64(m), 0)) * complex(norm, 0)
+		// psi(0) = (-1)^(m+1) * norm * d^m/dt^m exp(-t^2/2) at t = 0, which vanishes for odd orders
+		valueAtZero := 0.
+		if m%2 == 0 {
+			doubleFactorial := 1.
+			for k := m - 1; k > 1; k -= 2 {
+				doubleFactorial *= float64(k)
+			}
+			valueAtZero = math.Pow(-1, float64(m+1)) * norm * math.Pow(-1, float64(m/2)) * doubleFactorial
+		}
+		return wavelet{
+			fourier: func(w float64) complex128 {
+				return coefficient * complex(math.Pow(w, float64(m))*math.Exp(-w*w/2), 0)
+			},
+			periodFactor: 2 * math.Pi / math.Sqrt(float64(m)+0.5),
+			valueAtZero:  valueAtZero,
+		}, nil
+	default:
+		return wavelet{}, errors.New("wavelet not supported")
+	}
+}
+
+// CWT returns the continuous wavelet transform of the motion accelerations computed by FFT convolution.
+// The record mean is removed and the record is zero-padded to limit wrap-around effects.
+func CWT(motion ts.MotionData, options CWTOptions) (*Scalogram, error) {
+	if len(motion.Accelerations) < 2 {
+		return nil, errors.New("at least two samples are required")
+	}
+	if motion.TimeStep <= 0 {
+		return nil, errors.New("time step must be a positive number")
+	}
+	mother, err := getWavelet(options.Wavelet, options.Order)
+	if err != nil {
+		return nil, err
+	}
+	timeStep := motion.TimeStep
+	length := len(motion.Accelerations)
+	minScale := options.MinScale
+	if minScale == 0 {
+		minScale = 2 * timeStep
+	}
+	spacing := options.ScaleSpacing
+	if spacing == 0 {
+		spacing = 0.125
+	}
+	numScales := options.NumScales
+	if numScales == 0 {
+		numScales = int(math.Log2(float64(length)*timeStep/minScale)/spacing) + 1
+	}
+	if minScale < 0 || spacing < 0 || numScales < 1 {
+		return nil, errors.New("scales must be positive")
+	}
+
+	var mean float64
+	for _, acc := range motion.Accelerations {
+		mean += acc
+	}
+	mean /= float64(length)
+	numFFT := 2 * nextPowerOf2(length)
+	padded := make([]complex128, numFFT)
+	for i, acc := range motion.Accelerations {
+		padded[i] = complex(acc-mean, 0)
+	}
+	plan, err := fft.NewPlan(numFFT)
+	if err != nil {
+		return nil, err
+	}
+	transform := make([]complex128, numFFT)
+	plan.Forward(transform, padded)
+	omegas := angularFrequencies(numFFT, timeStep)
+
+	scalogram := Scalogram{
+		Times:        make([]float64, length),
+		Scales:       make([]float64, numScales),
+		Periods:      make([]float64, numScales),
+		Coefficients: make([][]complex128, numScales),
+		Power:        make([][]float64, numScales),
+		TimeStep:     timeStep,
+		ScaleSpacing: spacing,
+		wavelet:      mother,
+		mean:         mean,
+	}
+	for i := range scalogram.Times {
+		scalogram.Times[i] = startTime(motion) + float64(i)*timeStep
+	}
+
+	product := make([]complex128, numFFT)
+	for j := range scalogram.Scales {
+		scale := minScale * math.Pow(2, float64(j)*spacing)
+		norm := complex(math.Sqrt(2*math.Pi*scale/timeStep), 0)
+		for k, omega := range omegas {
+			product[k] = transform[k] * norm * cmplx.Conj(mother.fourier(scale*omega))
+		}
+		plan.Inverse(product, product)
+		coefficients := append([]complex128{}, product[:length]...)
+		power := make([]float64, length)
+		for i, c := range coefficients {
+			power[i] = real(c)*real(c) + imag(c)*imag(c)
+		}
+		scalogram.Scales[j] = scale
+		scalogram.Periods[j] = mother.periodFactor * scale
+		scalogram.Coefficients[j] = coefficients
+		scalogram.Power[j] = power
+	}
+	scalogram.cDelta = reconstructionFactor(mother)
+
+	return &scalogram, nil
+}
+
+func nextPowerOf2(n int) int {
+	power := 1
+	for power < n {
+		power *= 2
+	}
+	return power
+}
+
+func angularFrequencies(numFFT int, timeStep float64) []float64 {
+	omegas := make([]float64, numFFT)
+	for k := range omegas {
+		index := k
+		if k > numFFT/2 {
+			index = k - numFFT
+		}
+		omegas[k] = 2 * math.Pi * float64(index) / (float64(numFFT) * timeStep)
+	}
+	return omegas
+}
+
+// reconstructionFactor returns the reconstruction factor C_delta of the wavelet for continuously distributed
+// scales, sqrt(2*pi) / (2*psi(0)*ln2) times the integral of Re(psi_hat(u))/|u| (Torrence & Compo, 1998, Eq. 13).
+func reconstructionFactor(mother wavelet) float64 {
+	const step = 1e-3
+	var integral float64
+	for v := -20.; v < 5; v += step {
+		u := math.Exp(v)
+		integral += (real(mother.fourier(u)) + real(mother.fourier(-u))) * step
+	}
+	return math.Sqrt(2*math.Pi) * integral / (2 * mother.valueAtZero * math.Ln2)
+}
+
+func (s *Scalogram) scaleRange(minPeriod, maxPeriod float64) ([]int, error) {
+	var indexes []int
+	for j, period := range s.Periods {
+		if period >= minPeriod && period <= maxPeriod {
+			indexes = append(indexes, j)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, errors.New("no scales within the given period range")
+	}
+	return indexes, nil
+}
+
+// ScaleAveragedPower returns the time history of the wavelet power averaged over the scales whose Fourier periods
+// are between minPeriod and maxPeriod (Torrence & Compo, 1998, Eq. 24).
+func (s *Scalogram) ScaleAveragedPower(minPeriod, maxPeriod float64) ([]float64, error) {
+	indexes, err := s.scaleRange(minPeriod, maxPeriod)
+	if err != nil {
+		return nil, err
+	}
+	averaged := make([]float64, len(s.Times))
+	for _, j := range indexes {
+		for i, power := range s.Power[j] {
+			averaged[i] += power / s.Scales[j]
+		}
+	}
+	factor := s.ScaleSpacing * s.TimeStep / s.cDelta
+	for i := range averaged {
+		averaged[i] *= factor
+	}
+	return averaged, nil
+}
+
+// Reconstruct returns the part of the signal carried by the scales whose Fourier periods are between minPeriod
+// and maxPeriod (Torrence & Compo, 1998, Eq. 11). Subtracting it from the record removes that content. The record
+// mean is added back only when all scales are selected.
+func (s *Scalogram) Reconstruct(minPeriod, maxPeriod float64) ([]float64, error) {
+	if s.wavelet.valueAtZero == 0 {
+		return nil, errors.New("wavelet cannot be used for reconstruction")
+	}
+	indexes, err := s.scaleRange(minPeriod, maxPeriod)
+	if err != nil {
+		return nil, err
+	}
+	signal := make([]float64, len(s.Times))
+	for _, j := range indexes {
+		scaleFactor := 1 / math.Sqrt(s.Scales[j])
+		for i, c := range s.Coefficients[j] {
+			signal[i] += real(c) * scaleFactor
+		}
+	}
+	factor := s.ScaleSpacing * math.Sqrt(s.TimeStep) / (s.cDelta * s.wavelet.valueAtZero)
+	for i := range signal {
+		signal[i] *= factor
+		if len(indexes) == len(s.Scales) {
+			signal[i] += s.mean
+		}
+	}
+	return signal, nil
+}
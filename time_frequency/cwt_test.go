@@ -0,0 +1,116 @@
+package time_frequency
+
+import (
+	"math"
+	"testing"
+
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// sines returns the sum of unit amplitude sines with the given frequencies in Hz.
+func sines(length int, timeStep float64, frequencies ...float64) ts.MotionData {
+	accelerations := make([]float64, length)
+	for i := range accelerations {
+		for _, f := range frequencies {
+			accelerations[i] += math.Sin(2 * math.Pi * f * float64(i) * timeStep)
+		}
+	}
+	return ts.MotionData{Accelerations: accelerations, TimeStep: timeStep}
+}
+
+// relativeError returns the RMS difference of the signals over the middle half, away from edge effects, divided
+// by the RMS of the expected signal.
+func relativeError(signal, expected []float64) float64 {
+	var difference, total float64
+	for i := len(signal) / 4; i < 3*len(signal)/4; i++ {
+		difference += (signal[i] - expected[i]) * (signal[i] - expected[i])
+		total += expected[i] * expected[i]
+	}
+	return math.Sqrt(difference / total)
+}
+
+func TestCWT(t *testing.T) {
+	motion := sines(2000, 0.01, 2)
+	for _, name := range []string{"morlet", "mexican_hat"} {
+		scalogram, err := CWT(motion, CWTOptions{Wavelet: name})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if scalogram.Scales[0] != 0.02 || len(scalogram.Power) != len(scalogram.Scales) {
+			t.Errorf("Expected scales starting from twice the time step")
+		}
+		// the mean power over the middle of the record peaks near the 0.5 s period, the broadband mexican hat less sharply
+		peak, peakPower := 0, 0.
+		for j, power := range scalogram.Power {
+			var mean float64
+			for i := 500; i < 1500; i++ {
+				mean += power[i] / scalogram.Scales[j]
+			}
+			if mean > peakPower {
+				peak, peakPower = j, mean
+			}
+		}
+		if math.Abs(scalogram.Periods[peak]-0.5)/0.5 > 0.15 {
+			t.Errorf("Expected %s power to peak at 0.5 s, got %f s", name, scalogram.Periods[peak])
+		}
+	}
+
+	if _, err := CWT(motion, CWTOptions{Wavelet: "haar"}); err == nil {
+		t.Errorf("Expected error for unsupported wavelet")
+	}
+	if _, err := CWT(motion, CWTOptions{Wavelet: "dog"}); err == nil {
+		t.Errorf("Expected error for missing derivative of Gaussian order")
+	}
+}
+
+func TestScaleAveragedPower(t *testing.T) {
+	motion := sines(2000, 0.01, 2)
+	scalogram, _ := CWT(motion, CWTOptions{Wavelet: "morlet"})
+	power, err := scalogram.ScaleAveragedPower(0.01, 100)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// averaged over all scales the power equals the variance of the signal, 0.5 for a unit sine
+	var mean float64
+	for i := 500; i < 1500; i++ {
+		mean += power[i] / 1000
+	}
+	if math.Abs(mean-0.5) > 0.05 {
+		t.Errorf("Expected mean scale-averaged power of 0.5, got %f", mean)
+	}
+	if _, err = scalogram.ScaleAveragedPower(200, 300); err == nil {
+		t.Errorf("Expected error for period range without scales")
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	motion := sines(2000, 0.01, 1, 5)
+	low := sines(2000, 0.01, 1).Accelerations
+	high := sines(2000, 0.01, 5).Accelerations
+	for _, options := range []CWTOptions{{Wavelet: "morlet"}, {Wavelet: "dog", Order: 6}} {
+		scalogram, _ := CWT(motion, options)
+		signal, err := scalogram.Reconstruct(0, math.Inf(1))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if e := relativeError(signal, motion.Accelerations); e > 0.05 {
+			t.Errorf("Expected %s reconstruction within 5%%, got %f", options.Wavelet, e)
+		}
+		extracted, _ := scalogram.Reconstruct(0, 0.5)
+		if e := relativeError(extracted, high); e > 0.1 {
+			t.Errorf("Expected %s to extract the 5 Hz sine within 10%%, got %f", options.Wavelet, e)
+		}
+		removed := make([]float64, len(extracted))
+		for i := range removed {
+			removed[i] = motion.Accelerations[i] - extracted[i]
+		}
+		if e := relativeError(removed, low); e > 0.1 {
+			t.Errorf("Expected %s to remove the 5 Hz sine within 10%%, got %f", options.Wavelet, e)
+		}
+	}
+
+	scalogram, _ := CWT(motion, CWTOptions{Wavelet: "dog", Order: 1})
+	if _, err := scalogram.Reconstruct(0, math.Inf(1)); err == nil {
+		t.Errorf("Expected error for odd order derivative of Gaussian")
+	}
+}
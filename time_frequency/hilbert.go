@@ -0,0 +1,146 @@
+package time_frequency
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+
+	"github.com/geoport/GoQuakeLib/fft"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// HilbertOptions selects the channel of the motion and the smoothing of the instantaneous attributes.
+type HilbertOptions struct {
+	Channel         string  // "acceleration" (default), "velocity" or "displacement"
+	SmoothingLength float64 // centered moving average length in seconds for the envelope and frequency, 0 for none
+}
+
+// InstantaneousData holds the attributes of the analytic signal of a motion channel.
+type InstantaneousData struct {
+	Times       []float64
+	Signal      []float64 // the analyzed channel
+	Envelope    []float64 // amplitude of the analytic signal, in the unit of the channel
+	Phases      []float64 // unwrapped instantaneous phase in radians
+	Frequencies []float64 // instantaneous frequency in Hz
+}
+
+// AnalyticSignal returns the analytic signal x + iH[x] of the data computed with the FFT.
+func AnalyticSignal(data []float64) []complex128 {
+	length := len(data)
+	transform := fft.FFTReal(data)
+	for k := 1; k < length; k++ {
+		switch {
+		case 2*k < length:
+			transform[k] *= 2
+		case 2*k > length:
+			transform[k] = 0
+		}
+	}
+	return fft.IFFT(transform)
+}
+
+func getChannel(motion ts.MotionData, channel string) ([]float64, error) {
+	switch channel {
+	case "", "acceleration":
+		return motion.Accelerations, nil
+	case "velocity":
+		return motion.Velocities, nil
+	case "displacement":
+		return motion.Displacements, nil
+	default:
+		return nil, errors.New("channel not supported")
+	}
+}
+
+// movingAverage returns the centered moving average of the data over the given number of samples. The window is
+// shortened near the ends of the data.
+func movingAverage(data []float64, length int) []float64 {
+	if length < 2 {
+		return data
+	}
+	cumulative := make([]float64, len(data)+1)
+	for i, value := range data {
+		cumulative[i+1] = cumulative[i] + value
+	}
+	averaged := make([]float64, len(data))
+	for i := range data {
+		start := int(math.Max(float64(i-length/2), 0))
+		end := int(math.Min(float64(i-length/2+length), float64(len(data))))
+		averaged[i] = (cumulative[end] - cumulative[start]) / float64(end-start)
+	}
+	return averaged
+}
+
+// Hilbert returns the envelope, instantaneous phase and instantaneous frequency of a channel of the motion. The
+// instantaneous frequency is the central difference of the unwrapped phase divided by 2*pi.
+func Hilbert(motion ts.MotionData, options HilbertOptions) (*InstantaneousData, error) {
+	signal, err := getChannel(motion, options.Channel)
+	if err != nil {
+		return nil, err
+	}
+	if len(signal) < 2 {
+		return nil, errors.New("at least two samples are required")
+	}
+	if motion.TimeStep <= 0 {
+		return nil, errors.New("time step must be a positive number")
+	}
+	if options.SmoothingLength < 0 {
+		return nil, errors.New("smoothing length must not be negative")
+	}
+	timeStep := motion.TimeStep
+	length := len(signal)
+	analytic := AnalyticSignal(signal)
+
+	data := InstantaneousData{
+		Times:       make([]float64, length),
+		Signal:      signal,
+		Envelope:    make([]float64, length),
+		Phases:      make([]float64, length),
+		Frequencies: make([]float64, length),
+	}
+	var offset float64
+	for i, value := range analytic {
+		data.Times[i] = startTime(motion) + float64(i)*timeStep
+		data.Envelope[i] = cmplx.Abs(value)
+		phase := cmplx.Phase(value)
+		if i > 0 {
+			jump := phase + offset - data.Phases[i-1]
+			offset -= 2 * math.Pi * math.Round(jump/(2*math.Pi))
+		}
+		data.Phases[i] = phase + offset
+	}
+	for i := range data.Frequencies {
+		previous, next := int(math.Max(float64(i-1), 0)), int(math.Min(float64(i+1), float64(length-1)))
+		data.Frequencies[i] = (data.Phases[next] - data.Phases[previous]) / (float64(next-previous) * timeStep * 2 * math.Pi)
+	}
+
+	smoothingSamples := int(math.Round(options.SmoothingLength / timeStep))
+	data.Envelope = movingAverage(data.Envelope, smoothingSamples)
+	data.Frequencies = movingAverage(data.Frequencies, smoothingSamples)
+	return &data, nil
+}
+
+// EnvelopeDuration returns the time between the first and the last sample where the envelope reaches the given
+// fraction of its peak, together with the start time of that interval.
+func (d *InstantaneousData) EnvelopeDuration(fraction float64) (float64, float64, error) {
+	if fraction <= 0 || fraction >= 1 {
+		return 0, 0, errors.New("fraction must be between 0 and 1")
+	}
+	var peak float64
+	for _, value := range d.Envelope {
+		peak = math.Max(peak, value)
+	}
+	if peak == 0 {
+		return 0, 0, errors.New("envelope is zero")
+	}
+	first, last := -1, -1
+	for i, value := range d.Envelope {
+		if value >= fraction*peak {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	return d.Times[last] - d.Times[first], d.Times[first], nil
+}
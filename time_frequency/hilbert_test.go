@@ -0,0 +1,77 @@
+package time_frequency
+
+import (
+	"math"
+	"testing"
+
+	ts "github.com/geoport/GoQuakeLib/time_series"
+)
+
+// modulatedSine returns a 5 Hz sine whose amplitude varies as 1 + 0.5cos(2*pi*0.2t).
+func modulatedSine(length int, timeStep float64) (ts.MotionData, []float64) {
+	accelerations := make([]float64, length)
+	envelope := make([]float64, length)
+	for i := range accelerations {
+		time := float64(i) * timeStep
+		envelope[i] = 1 + 0.5*math.Cos(2*math.Pi*0.2*time)
+		accelerations[i] = envelope[i] * math.Sin(2*math.Pi*5*time)
+	}
+	return ts.MotionData{Accelerations: accelerations, TimeStep: timeStep}, envelope
+}
+
+func TestAnalyticSignal(t *testing.T) {
+	data := []float64{1, 2, 3, 4}
+	analytic := AnalyticSignal(data)
+	// scipy.signal.hilbert([1, 2, 3, 4])
+	expected := []complex128{1 + 1i, 2 - 1i, 3 - 1i, 4 + 1i}
+	for i := range expected {
+		if math.Abs(real(analytic[i])-real(expected[i])) > 1e-12 || math.Abs(imag(analytic[i])-imag(expected[i])) > 1e-12 {
+			t.Errorf("Expected %v at %d, got %v", expected[i], i, analytic[i])
+		}
+	}
+}
+
+func TestHilbert(t *testing.T) {
+	motion, envelope := modulatedSine(2000, 0.01)
+	data, err := Hilbert(motion, HilbertOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i := 100; i < 1900; i++ {
+		if math.Abs(data.Envelope[i]-envelope[i]) > 0.01 {
+			t.Fatalf("Expected envelope %f at %d, got %f", envelope[i], i, data.Envelope[i])
+		}
+		if math.Abs(data.Frequencies[i]-5) > 0.05 {
+			t.Fatalf("Expected instantaneous frequency of 5 Hz at %d, got %f", i, data.Frequencies[i])
+		}
+	}
+	if data.Phases[1999] < data.Phases[0]+2*math.Pi*5*19 {
+		t.Errorf("Expected the phase to be unwrapped")
+	}
+
+	smoothed, _ := Hilbert(motion, HilbertOptions{SmoothingLength: 0.2})
+	if math.Abs(smoothed.Envelope[1000]-data.Envelope[1000]) > 0.01 {
+		t.Errorf("Expected smoothing to keep the slowly varying envelope")
+	}
+
+	if _, err = Hilbert(motion, HilbertOptions{Channel: "jerk"}); err == nil {
+		t.Errorf("Expected error for unsupported channel")
+	}
+	if _, err = Hilbert(motion, HilbertOptions{Channel: "velocity"}); err == nil {
+		t.Errorf("Expected error for missing velocities")
+	}
+}
+
+func TestEnvelopeDuration(t *testing.T) {
+	data := InstantaneousData{
+		Times:    []float64{0, 1, 2, 3, 4, 5},
+		Envelope: []float64{0, 0.2, 1, 0.3, 0.05, 0},
+	}
+	duration, start, err := data.EnvelopeDuration(0.1)
+	if err != nil || duration != 2 || start != 1 {
+		t.Errorf("Expected duration 2 starting at 1, got %f starting at %f", duration, start)
+	}
+	if _, _, err = data.EnvelopeDuration(1); err == nil {
+		t.Errorf("Expected error for fraction of one")
+	}
+}
@@ -0,0 +1,217 @@
+package time_frequency
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+
+	"github.com/geoport/GoQuakeLib/fft"
+	fs "github.com/geoport/GoQuakeLib/fourier_spectrum"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+// STFTOptions controls the framing of the short-time Fourier transform. All lengths are in samples.
+type STFTOptions struct {
+	WindowLength  int     // number of samples in each frame
+	HopLength     int     // number of samples between the centers of consecutive frames
+	NFFT          int     // FFT length, at least WindowLength (frames are zero-padded), 0 uses WindowLength
+	Window        string  // taper window applied to each frame, see fourier_spectrum.GetWindow
+	TaperFraction float64 // fraction of the "tukey" window inside the cosine tapers, in [0, 1]
+}
+
+// Spectrogram holds the time-frequency amplitudes of a record.
+type Spectrogram struct {
+	Times           []float64   // center time of each frame, on the time axis of the motion
+	Frequencies     []float64   // Hz
+	Amplitudes      [][]float64 // Fourier amplitudes of each frame, [frame][frequency]
+	MeanFrequencies []float64   // amplitude-squared weighted mean frequency of each frame
+	TimeStep        float64
+	HopLength       int
+}
+
+// DefaultSTFTOptions returns hann windowed frames of 128 samples with a hop of 16 samples and an FFT length of 256. A
+// "tukey" window tapers 10% of the frames.
+func DefaultSTFTOptions() STFTOptions {
+	return STFTOptions{WindowLength: 128, HopLength: 16, NFFT: 256, Window: "hann", TaperFraction: 0.1}
+}
+
+func checkSTFTInput(motion ts.MotionData, options STFTOptions) error {
+	if len(motion.Accelerations) == 0 {
+		return errors.New("no acceleration data")
+	}
+	if motion.TimeStep <= 0 {
+		return errors.New("time step must be a positive number")
+	}
+	if options.WindowLength < 2 || options.HopLength < 1 {
+		return errors.New("window length must be at least 2 and hop length at least 1")
+	}
+	if options.NFFT != 0 && options.NFFT < options.WindowLength {
+		return errors.New("FFT length must not be smaller than the window length")
+	}
+	if options.TaperFraction < 0 || options.TaperFraction > 1 {
+		return errors.New("taper fraction must be between 0 and 1")
+	}
+	return nil
+}
+
+func startTime(motion ts.MotionData) float64 {
+	if len(motion.Times) > 0 {
+		return motion.Times[0]
+	}
+	return 0
+}
+
+// STFT returns the spectrogram of the motion accelerations. Frames are centered on every HopLength-th sample,
+// starting from the first one, and the record is zero-padded at both ends.
+func STFT(motion ts.MotionData, options STFTOptions) (*Spectrogram, error) {
+	if err := checkSTFTInput(motion, options); err != nil {
+		return nil, err
+	}
+	window, err := fs.GetWindow(options.Window, options.WindowLength, options.TaperFraction)
+	if err != nil {
+		return nil, err
+	}
+	nfft := options.NFFT
+	if nfft == 0 {
+		nfft = options.WindowLength
+	}
+	accelerations := motion.Accelerations
+	timeStep := motion.TimeStep
+	numFrequencies := nfft/2 + 1
+	numFrames := (len(accelerations)-1)/options.HopLength + 1
+	half := options.WindowLength / 2
+
+	spectrogram := Spectrogram{
+		Times:           make([]float64, numFrames),
+		Frequencies:     make([]float64, numFrequencies),
+		Amplitudes:      make([][]float64, numFrames),
+		MeanFrequencies: make([]float64, numFrames),
+		TimeStep:        timeStep,
+		HopLength:       options.HopLength,
+	}
+	for k := range spectrogram.Frequencies {
+		spectrogram.Frequencies[k] = float64(k) / (float64(nfft) * timeStep)
+	}
+
+	plan, err := fft.NewRealPlan(nfft)
+	if err != nil {
+		return nil, err
+	}
+	frame := make([]float64, nfft)
+	transform := make([]complex128, numFrequencies)
+	for j := 0; j < numFrames; j++ {
+		center := j * options.HopLength
+		for i := range frame {
+			frame[i] = 0
+		}
+		for i := 0; i < options.WindowLength; i++ {
+			index := center - half + i
+			if index >= 0 && index < len(accelerations) {
+				frame[i] = accelerations[index] * window[i]
+			}
+		}
+		plan.Forward(transform, frame)
+
+		amplitudes := make([]float64, numFrequencies)
+		var power, weightedPower float64
+		for k := range amplitudes {
+			amplitudes[k] = cmplx.Abs(transform[k]) * timeStep
+			power += amplitudes[k] * amplitudes[k]
+			weightedPower += spectrogram.Frequencies[k] * amplitudes[k] * amplitudes[k]
+		}
+		spectrogram.Amplitudes[j] = amplitudes
+		spectrogram.Times[j] = startTime(motion) + float64(center)*timeStep
+		if power > 0 {
+			spectrogram.MeanFrequencies[j] = weightedPower / power
+		}
+	}
+	return &spectrogram, nil
+}
+
+// husid returns the normalized cumulative Arias intensity of the accelerations.
+func husid(accelerations []float64, timeStep float64) []float64 {
+	ia := np.Cumtrapz(np.Pow(accelerations, 2), timeStep, 0)
+	total := ia[len(ia)-1]
+	if total == 0 {
+		return ia
+	}
+	return np.MultiplyBy(ia, 1/total)
+}
+
+// FrameEnergies returns the fraction of the Arias intensity of the motion arriving within the hop interval of each
+// frame of the spectrogram. The fractions sum to one. The motion must be the one the spectrogram was computed from.
+func (s *Spectrogram) FrameEnergies(motion ts.MotionData) ([]float64, error) {
+	if len(motion.Accelerations) == 0 {
+		return nil, errors.New("no acceleration data")
+	}
+	if s.HopLength < 1 || (len(motion.Accelerations)-1)/s.HopLength+1 != len(s.Times) || motion.TimeStep != s.TimeStep {
+		return nil, errors.New("motion does not match the frames of the spectrogram")
+	}
+	h := husid(motion.Accelerations, motion.TimeStep)
+	lastIndex := len(h) - 1
+	energies := make([]float64, len(s.Times))
+	for j := range energies {
+		center := j * s.HopLength
+		start := int(math.Max(float64(center-s.HopLength/2), 0))
+		end := int(math.Min(float64(center+s.HopLength-s.HopLength/2), float64(lastIndex)))
+		if j == 0 {
+			start = 0
+		}
+		if j == len(energies)-1 {
+			end = lastIndex
+		}
+		if end > start {
+			energies[j] = h[end] - h[start]
+		}
+	}
+	return energies, nil
+}
+
+// HusidWeightedSpectrum returns the average amplitude spectrum of the frames weighted by the Arias intensity
+// arriving in each frame, together with the energy-weighted mean frequency of the record.
+func (s *Spectrogram) HusidWeightedSpectrum(motion ts.MotionData) ([]float64, float64, error) {
+	energies, err := s.FrameEnergies(motion)
+	if err != nil {
+		return nil, 0, err
+	}
+	spectrum := make([]float64, len(s.Frequencies))
+	var meanFrequency, totalEnergy float64
+	for j, energy := range energies {
+		for k, amplitude := range s.Amplitudes[j] {
+			spectrum[k] += energy * amplitude
+		}
+		meanFrequency += energy * s.MeanFrequencies[j]
+		totalEnergy += energy
+	}
+	if totalEnergy == 0 {
+		return nil, 0, errors.New("record has no energy")
+	}
+	return np.MultiplyBy(spectrum, 1/totalEnergy), meanFrequency / totalEnergy, nil
+}
+
+// MeanFrequencyBetween returns the energy-weighted mean frequency of the frames whose centers lie between the
+// times at which the Husid plot reaches the given fractions (e.g. 0.05 and 0.95).
+func (s *Spectrogram) MeanFrequencyBetween(motion ts.MotionData, startFraction, endFraction float64) (float64, error) {
+	if startFraction < 0 || endFraction > 1 || startFraction >= endFraction {
+		return 0, errors.New("husid fractions must satisfy 0 <= start < end <= 1")
+	}
+	energies, err := s.FrameEnergies(motion)
+	if err != nil {
+		return 0, err
+	}
+	h := husid(motion.Accelerations, motion.TimeStep)
+	var meanFrequency, totalEnergy float64
+	for j, energy := range energies {
+		center := int(math.Min(float64(j*s.HopLength), float64(len(h)-1)))
+		if h[center] < startFraction || h[center] > endFraction {
+			continue
+		}
+		meanFrequency += energy * s.MeanFrequencies[j]
+		totalEnergy += energy
+	}
+	if totalEnergy == 0 {
+		return 0, errors.New("no energy between the given fractions")
+	}
+	return meanFrequency / totalEnergy, nil
+}
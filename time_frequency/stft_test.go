@@ -0,0 +1,116 @@
+package time_frequency
+
+import (
+	td "github.com/geoport/GoQuakeLib/TestData"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	"math"
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+var testMotion = ts.MotionData{
+	Accelerations: td.TestMotion["Accelerations"].([]float64),
+	TimeStep:      td.TestMotion["TimeStep"].(float64),
+	Times:         td.TestMotion["Times"].([]float64),
+}
+
+// chirp returns a unit amplitude sine whose frequency grows linearly from f0 to f1 Hz over the record.
+func chirp(length int, timeStep, f0, f1 float64) ts.MotionData {
+	duration := float64(length) * timeStep
+	accelerations := make([]float64, length)
+	for i := range accelerations {
+		time := float64(i) * timeStep
+		accelerations[i] = math.Sin(2 * math.Pi * (f0*time + (f1-f0)*time*time/(2*duration)))
+	}
+	return ts.MotionData{Accelerations: accelerations, TimeStep: timeStep}
+}
+
+func TestSTFT(t *testing.T) {
+	motion := chirp(4000, 0.01, 2, 10)
+	spectrogram, err := STFT(motion, DefaultSTFTOptions())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(spectrogram.Times) != 250 || spectrogram.Times[1] != 0.16 {
+		t.Errorf("Expected 250 frames every 0.16 s, got %d frames", len(spectrogram.Times))
+	}
+	if len(spectrogram.Frequencies) != 129 {
+		t.Errorf("Expected 129 frequencies, got %d", len(spectrogram.Frequencies))
+	}
+	// instantaneous frequency is 2 + 8t/40 Hz
+	for _, j := range []int{25, 125, 225} {
+		expected := 2 + 8*spectrogram.Times[j]/40
+		if math.Abs(spectrogram.MeanFrequencies[j]-expected) > 0.2 {
+			t.Errorf("Expected mean frequency %f at %f s, got %f", expected, spectrogram.Times[j], spectrogram.MeanFrequencies[j])
+		}
+	}
+
+	spectrogram, _ = STFT(testMotion, DefaultSTFTOptions())
+	if spectrogram.Times[len(spectrogram.Times)-1] > testMotion.Times[len(testMotion.Times)-1] {
+		t.Errorf("Expected frame times within the record")
+	}
+
+	options := DefaultSTFTOptions()
+	options.NFFT = 64
+	if _, err = STFT(motion, options); err == nil {
+		t.Errorf("Expected error for FFT length shorter than the window")
+	}
+
+	// an untapered tukey window is a boxcar window
+	boxcar := DefaultSTFTOptions()
+	boxcar.Window = "boxcar"
+	tukey := DefaultSTFTOptions()
+	tukey.Window, tukey.TaperFraction = "tukey", 0
+	expected, _ := STFT(motion, boxcar)
+	spectrogram, err = STFT(motion, tukey)
+	if err != nil || !np.AllClose(spectrogram.Amplitudes[10], expected.Amplitudes[10], 1e-12) {
+		t.Errorf("Expected an untapered tukey window to equal the boxcar window (%v)", err)
+	}
+	tukey.TaperFraction = 1.5
+	if _, err = STFT(motion, tukey); err == nil {
+		t.Errorf("Expected error for a taper fraction above 1")
+	}
+}
+
+func TestSpectrogram_FrameEnergies(t *testing.T) {
+	spectrogram, _ := STFT(testMotion, DefaultSTFTOptions())
+	energies, err := spectrogram.FrameEnergies(testMotion)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if np.Round(np.Sum(energies), 10) != 1. {
+		t.Errorf("Expected frame energies to sum to 1, got %f", np.Sum(energies))
+	}
+	short := testMotion
+	short.Accelerations = short.Accelerations[:len(short.Accelerations)/2]
+	if _, err = spectrogram.FrameEnergies(short); err == nil {
+		t.Errorf("Expected error for a motion shorter than the frames")
+	}
+}
+
+func TestSpectrogram_HusidWeightedSpectrum(t *testing.T) {
+	motion := chirp(4000, 0.01, 5, 5)
+	spectrogram, _ := STFT(motion, DefaultSTFTOptions())
+	spectrum, meanFrequency, err := spectrogram.HusidWeightedSpectrum(motion)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	peakFrequency := spectrogram.Frequencies[np.ArgMax(spectrum)]
+	if math.Abs(peakFrequency-5) > 0.4 || math.Abs(meanFrequency-5) > 0.2 {
+		t.Errorf("Expected spectrum peak and mean frequency near 5 Hz, got %f and %f", peakFrequency, meanFrequency)
+	}
+}
+
+func TestSpectrogram_MeanFrequencyBetween(t *testing.T) {
+	motion := chirp(4000, 0.01, 2, 10)
+	spectrogram, _ := STFT(motion, DefaultSTFTOptions())
+	early, _ := spectrogram.MeanFrequencyBetween(motion, 0, 0.5)
+	late, _ := spectrogram.MeanFrequencyBetween(motion, 0.5, 1)
+	if early >= late || math.Abs(early-4) > 0.3 || math.Abs(late-8) > 0.3 {
+		t.Errorf("Expected mean frequencies near 4 and 8 Hz, got %f and %f", early, late)
+	}
+	if _, err := spectrogram.MeanFrequencyBetween(motion, 0.9, 0.1); err == nil {
+		t.Errorf("Expected error for reversed fractions")
+	}
+}
@@ -0,0 +1,198 @@
+package transfer_function
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"sort"
+
+	fs "github.com/geoport/GoQuakeLib/fourier_spectrum"
+	rs "github.com/geoport/GoQuakeLib/response_spectra"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+// SpectralRatioData holds the ratio of the smoothed Fourier amplitude spectra of an output and an input record.
+type SpectralRatioData struct {
+	Frequencies    []float64
+	Ratios         []float64 // output / input
+	InputSpectrum  []float64 // smoothed Fourier amplitudes of the input (cm/s)
+	OutputSpectrum []float64 // smoothed Fourier amplitudes of the output (cm/s)
+}
+
+// FrequencyResponseData holds the H1 and H2 estimates of the frequency response function from the input to the
+// output record together with their coherence.
+type FrequencyResponseData struct {
+	Frequencies  []float64
+	H1           []complex128 // cross spectrum / input auto spectrum, unbiased by output noise
+	H2           []complex128 // output auto spectrum / cross spectrum, unbiased by input noise
+	H1Amplitudes []float64
+	H2Amplitudes []float64
+	Phases       []float64 // phase of H1 in radians
+	Coherences   []float64 // magnitude-squared coherence, equal to H1/H2
+}
+
+// ResponseSpectralRatioData holds the ratio of the response spectra of an output and an input record.
+type ResponseSpectralRatioData struct {
+	Periods       []float64
+	Ratios        []float64 // ratio of spectral accelerations, output / input
+	InputSpectra  *rs.ResponseSpectraData
+	OutputSpectra *rs.ResponseSpectraData
+}
+
+// Peak is a local maximum of a transfer function.
+type Peak struct {
+	Index      int
+	Frequency  float64
+	Amplitude  float64
+	Prominence float64 // height above the higher of the two surrounding minima
+}
+
+func checkPair(input, output ts.MotionData) error {
+	if len(input.Accelerations) == 0 || len(output.Accelerations) == 0 {
+		return errors.New("no acceleration data")
+	}
+	if len(input.Accelerations) != len(output.Accelerations) {
+		return errors.New("records must be synchronous and of equal length")
+	}
+	if input.TimeStep != output.TimeStep {
+		return errors.New("records must have the same time step")
+	}
+	return nil
+}
+
+// SmoothedSpectralRatio returns the ratio of the Konno-Ohmachi smoothed Fourier amplitude spectra of the output
+// and input records evaluated at the given frequencies.
+func SmoothedSpectralRatio(
+	input, output ts.MotionData, frequencies []float64, bandwidth float64,
+) (*SpectralRatioData, error) {
+	if err := checkPair(input, output); err != nil {
+		return nil, err
+	}
+	inputSpectrum, err := fs.MotionFourierSpectrum(input, fs.SpectrumOptions{})
+	if err != nil {
+		return nil, err
+	}
+	outputSpectrum, err := fs.MotionFourierSpectrum(output, fs.SpectrumOptions{})
+	if err != nil {
+		return nil, err
+	}
+	smoother, err := fs.NewKonnoOhmachiSmoother(inputSpectrum.Frequencies, frequencies, bandwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	ratioData := SpectralRatioData{Frequencies: smoother.Frequencies}
+	ratioData.InputSpectrum, _ = smoother.Apply(inputSpectrum.Amplitudes)
+	ratioData.OutputSpectrum, _ = smoother.Apply(outputSpectrum.Amplitudes)
+	ratioData.Ratios = make([]float64, len(ratioData.Frequencies))
+	for i := range ratioData.Ratios {
+		if ratioData.InputSpectrum[i] > 0 {
+			ratioData.Ratios[i] = ratioData.OutputSpectrum[i] / ratioData.InputSpectrum[i]
+		}
+	}
+	return &ratioData, nil
+}
+
+// FrequencyResponse returns the H1 and H2 frequency response estimates from the input to the output record
+// computed with Welch averaged auto and cross spectra.
+func FrequencyResponse(input, output ts.MotionData, options fs.WelchOptions) (*FrequencyResponseData, error) {
+	if err := checkPair(input, output); err != nil {
+		return nil, err
+	}
+	spectra, err := fs.WelchSpectra(input, output, options)
+	if err != nil {
+		return nil, err
+	}
+
+	numFrequencies := len(spectra.Frequencies)
+	response := FrequencyResponseData{
+		Frequencies:  spectra.Frequencies,
+		H1:           make([]complex128, numFrequencies),
+		H2:           make([]complex128, numFrequencies),
+		H1Amplitudes: make([]float64, numFrequencies),
+		H2Amplitudes: make([]float64, numFrequencies),
+		Phases:       make([]float64, numFrequencies),
+		Coherences:   make([]float64, numFrequencies),
+	}
+	for k, pxy := range spectra.CrossDensities {
+		pxx := spectra.Densities1[k]
+		pyy := spectra.Densities2[k]
+		if pxx > 0 {
+			response.H1[k] = pxy / complex(pxx, 0)
+		}
+		if pxy != 0 {
+			response.H2[k] = complex(pyy, 0) / cmplx.Conj(pxy)
+		}
+		if pxx > 0 && pyy > 0 {
+			response.Coherences[k] = math.Pow(cmplx.Abs(pxy), 2) / (pxx * pyy)
+		}
+		response.H1Amplitudes[k] = cmplx.Abs(response.H1[k])
+		response.H2Amplitudes[k] = cmplx.Abs(response.H2[k])
+		response.Phases[k] = cmplx.Phase(response.H1[k])
+	}
+	return &response, nil
+}
+
+// ResponseSpectralRatio returns the ratio of the spectral accelerations of the output and input records.
+func ResponseSpectralRatio(
+	input, output ts.MotionData, periods []float64, damping float64,
+) (*ResponseSpectralRatioData, error) {
+	if err := checkPair(input, output); err != nil {
+		return nil, err
+	}
+	if len(periods) == 0 {
+		return nil, errors.New("periods are empty")
+	}
+	ratioData := ResponseSpectralRatioData{
+		Periods:       periods,
+		InputSpectra:  rs.ResponseSpectra(input.Accelerations, input.TimeStep, periods, damping),
+		OutputSpectra: rs.ResponseSpectra(output.Accelerations, output.TimeStep, periods, damping),
+	}
+	ratioData.Ratios = np.DividedBy(
+		ratioData.OutputSpectra.SpectralAccelerations, ratioData.InputSpectra.SpectralAccelerations,
+	)
+	return &ratioData, nil
+}
+
+// FindPeaks returns the local maxima of the amplitudes whose prominence is at least minProminence, sorted by
+// decreasing amplitude. The first peak is the strongest resonance, which is not necessarily the fundamental one, see
+// FundamentalPeak.
+func FindPeaks(frequencies, amplitudes []float64, minProminence float64) []Peak {
+	var peaks []Peak
+	for i := 1; i < len(amplitudes)-1; i++ {
+		if amplitudes[i] <= amplitudes[i-1] || amplitudes[i] < amplitudes[i+1] {
+			continue
+		}
+		leftMin := amplitudes[i]
+		for j := i - 1; j >= 0 && amplitudes[j] <= amplitudes[i]; j-- {
+			leftMin = math.Min(leftMin, amplitudes[j])
+		}
+		rightMin := amplitudes[i]
+		for j := i + 1; j < len(amplitudes) && amplitudes[j] <= amplitudes[i]; j++ {
+			rightMin = math.Min(rightMin, amplitudes[j])
+		}
+		prominence := amplitudes[i] - math.Max(leftMin, rightMin)
+		if prominence >= minProminence {
+			peaks = append(
+				peaks, Peak{Index: i, Frequency: frequencies[i], Amplitude: amplitudes[i], Prominence: prominence},
+			)
+		}
+	}
+	sort.Slice(peaks, func(a, b int) bool { return peaks[a].Amplitude > peaks[b].Amplitude })
+	return peaks
+}
+
+// FundamentalPeak returns the lowest frequency peak, the estimated fundamental resonance.
+func FundamentalPeak(peaks []Peak) (Peak, error) {
+	if len(peaks) == 0 {
+		return Peak{}, errors.New("no peaks found")
+	}
+	fundamental := peaks[0]
+	for _, peak := range peaks[1:] {
+		if peak.Frequency < fundamental.Frequency {
+			fundamental = peak
+		}
+	}
+	return fundamental, nil
+}
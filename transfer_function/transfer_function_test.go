@@ -0,0 +1,116 @@
+package transfer_function
+
+import (
+	td "github.com/geoport/GoQuakeLib/TestData"
+	fs "github.com/geoport/GoQuakeLib/fourier_spectrum"
+	ts "github.com/geoport/GoQuakeLib/time_series"
+	"math"
+	"math/rand"
+	"testing"
+
+	np "github.com/geoport/numpy4go/vectors"
+)
+
+var testMotion = ts.MotionData{
+	Accelerations: td.TestMotion["Accelerations"].([]float64),
+	TimeStep:      td.TestMotion["TimeStep"].(float64),
+}
+
+func scaledMotion(motion ts.MotionData, factor float64) ts.MotionData {
+	motion.Accelerations = np.MultiplyBy(motion.Accelerations, factor)
+	return motion
+}
+
+// resonantPair returns a white noise input and its response through a band-pass resonator at 2 Hz.
+func resonantPair() (ts.MotionData, ts.MotionData) {
+	timeStep := 0.01
+	random := rand.New(rand.NewSource(11))
+	input := make([]float64, 20000)
+	output := make([]float64, len(input))
+	r := 0.98
+	a1 := 2 * r * math.Cos(2*math.Pi*2*timeStep)
+	for i := range input {
+		input[i] = random.NormFloat64()
+		output[i] = input[i]
+		if i > 1 {
+			output[i] += -input[i-2] + a1*output[i-1] - r*r*output[i-2]
+		}
+	}
+	return ts.MotionData{Accelerations: input, TimeStep: timeStep}, ts.MotionData{Accelerations: output, TimeStep: timeStep}
+}
+
+func TestSmoothedSpectralRatio(t *testing.T) {
+	frequencies := fs.LogSpacedFrequencies(0.1, 9, 100)
+	ratio, err := SmoothedSpectralRatio(testMotion, scaledMotion(testMotion, 3), frequencies, 40)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !np.AllClose(ratio.Ratios, np.Repeat(3, len(frequencies)), 1e-9) {
+		t.Errorf("Expected constant ratio of 3, got %v", ratio.Ratios[:5])
+	}
+
+	input, output := resonantPair()
+	ratio, _ = SmoothedSpectralRatio(input, output, fs.LogSpacedFrequencies(0.5, 10, 200), 40)
+	peaks := FindPeaks(ratio.Frequencies, ratio.Ratios, 1)
+	if len(peaks) == 0 || math.Abs(peaks[0].Frequency-2) > 0.2 {
+		t.Errorf("Expected resonance near 2 Hz, got %v", peaks)
+	}
+}
+
+func TestFrequencyResponse(t *testing.T) {
+	input, output := resonantPair()
+	options := fs.DefaultWelchOptions()
+	options.SegmentLength = 2048
+	response, err := FrequencyResponse(input, output, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	peaks := FindPeaks(response.Frequencies, response.H1Amplitudes, 1)
+	if len(peaks) == 0 || math.Abs(peaks[0].Frequency-2) > 0.2 {
+		t.Errorf("Expected H1 resonance near 2 Hz, got %v", peaks)
+	}
+	// a noise free linear system has a coherence close to one and nearly identical H1 and H2
+	index := peaks[0].Index
+	if response.Coherences[index] < 0.98 {
+		t.Errorf("Expected unit coherence at resonance, got %f", response.Coherences[index])
+	}
+	if math.Abs(response.H1Amplitudes[index]-response.H2Amplitudes[index]) > 0.02*response.H1Amplitudes[index] {
+		t.Errorf("Expected equal H1 and H2, got %f and %f", response.H1Amplitudes[index], response.H2Amplitudes[index])
+	}
+}
+
+func TestResponseSpectralRatio(t *testing.T) {
+	periods := np.Arange(0.1, 4, 0.1)
+	ratio, err := ResponseSpectralRatio(testMotion, scaledMotion(testMotion, 2), periods, 0.05)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !np.AllClose(ratio.Ratios, np.Repeat(2, len(periods)), 1e-9) {
+		t.Errorf("Expected constant ratio of 2, got %v", ratio.Ratios[:5])
+	}
+
+	short := testMotion
+	short.Accelerations = short.Accelerations[:100]
+	if _, err = ResponseSpectralRatio(testMotion, short, periods, 0.05); err == nil {
+		t.Errorf("Expected error for records of different length")
+	}
+}
+
+func TestFindPeaks(t *testing.T) {
+	frequencies := []float64{1, 2, 3, 4, 5, 6, 7}
+	amplitudes := []float64{1, 3, 1, 2, 1.8, 5, 1}
+	peaks := FindPeaks(frequencies, amplitudes, 0.5)
+	if len(peaks) != 2 || peaks[0].Frequency != 6 || peaks[1].Frequency != 2 {
+		t.Errorf("Expected peaks at 6 and 2 Hz, got %v", peaks)
+	}
+	if peaks[1].Prominence != 2 {
+		t.Errorf("Expected prominence 2, got %f", peaks[1].Prominence)
+	}
+	// the weaker peak at the lower frequency is the fundamental resonance
+	if fundamental, err := FundamentalPeak(peaks); err != nil || fundamental.Frequency != 2 {
+		t.Errorf("Expected the fundamental peak at 2 Hz, got %v", fundamental)
+	}
+	if _, err := FundamentalPeak(nil); err == nil {
+		t.Errorf("Expected error for no peaks")
+	}
+}